@@ -25,8 +25,10 @@ import (
 )
 
 type configuration struct {
-	CredentialsRetryTime int
-	CredentialsRetryWait int
+	CredentialsRetryTime     int
+	CredentialsRetryWait     int
+	AudioClipDirectory       string
+	CertificateAuthorityPath string
 }
 
 type cameraInfo struct {
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -34,11 +35,15 @@ import (
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
 
 	"github.com/faceterteam/onvif4go/onvif"
+	"github.com/faceterteam/onvif4go/xsd"
 
 	"github.com/edgexfoundry/device-camera-go/internal/pkg/axis"
 	"github.com/edgexfoundry/device-camera-go/internal/pkg/bosch"
 	"github.com/edgexfoundry/device-camera-go/internal/pkg/client"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/hanwha"
 	"github.com/edgexfoundry/device-camera-go/internal/pkg/noop"
+	tds "github.com/edgexfoundry/device-camera-go/internal/pkg/onvif/device"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/rtsp"
 )
 
 var once sync.Once
@@ -199,6 +204,102 @@ func (d *Driver) HandleReadCommands(deviceName string, protocols map[string]mode
 				return responses, err
 			}
 
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifRemoteUser":
+			data, err = onvifClient.GetRemoteUser()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifWsdlUrl":
+			data, err = onvifClient.GetWsdlUrl()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifEndpointReference":
+			data, err = onvifClient.GetEndpointReference()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifAccessPolicy":
+			data, err = onvifClient.GetAccessPolicy()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifIPAddressFilter":
+			data, err = onvifClient.GetIPAddressFilter()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifCertificates":
+			data, err = onvifClient.GetCertificates()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifCertificatesStatus":
+			data, err = onvifClient.GetCertificatesStatus()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifAudioOutputConfigurations":
+			data, err = onvifClient.GetAudioOutputConfigurations()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifAudioDecoderConfigurations":
+			data, err = onvifClient.GetAudioDecoderConfigurations()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifThermalConfiguration":
+			data, err = onvifClient.GetThermalConfiguration()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "OnvifRadiometryConfiguration":
+			data, err = onvifClient.GetRadiometryConfiguration()
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
+			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
+		case "MotionRegions":
+			data, err = d.getMotionRegions(onvifClient, c, req)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return responses, err
+			}
+
 			cv, err = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, common.ValueTypeString, string(data))
 		// camera specific cases
 		default:
@@ -334,6 +435,277 @@ func (d *Driver) HandleWriteCommands(deviceName string, protocols map[string]mod
 				return err
 			}
 
+		case "OnvifRemoteUser":
+			var remoteUser onvif.RemoteUser
+			err := structFromParam(params[i], &remoteUser)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			// a remote user without a name removes the remote user
+			var user *onvif.RemoteUser
+			if remoteUser.Username != "" {
+				user = &remoteUser
+			}
+
+			err = onvifClient.SetRemoteUser(user)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifAccessPolicy":
+			var policy tds.BinaryData
+			err := structFromParam(params[i], &policy)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			err = onvifClient.SetAccessPolicy(policy)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifIPAddressFilter", "OnvifAddIPAddressFilter", "OnvifRemoveIPAddressFilter":
+			var filter tds.IPAddressFilter
+			err := structFromParam(params[i], &filter)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			switch req.DeviceResourceName {
+			case "OnvifIPAddressFilter":
+				err = onvifClient.SetIPAddressFilter(filter)
+			case "OnvifAddIPAddressFilter":
+				err = onvifClient.AddIPAddressFilter(filter)
+			default:
+				err = onvifClient.RemoveIPAddressFilter(filter)
+			}
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifNetworkProtocols":
+			var protocols []struct {
+				Name    string
+				Enabled bool
+				Port    int
+			}
+			err := structFromParam(params[i], &protocols)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			var networkProtocols []onvif.NetworkProtocol
+			for _, p := range protocols {
+				networkProtocols = append(networkProtocols, onvif.NetworkProtocol{
+					Name:    onvif.NetworkProtocolType(p.Name),
+					Enabled: p.Enabled,
+					Port:    p.Port,
+				})
+			}
+
+			err = onvifClient.SetNetworkProtocols(networkProtocols)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifCertificates":
+			certificate := struct {
+				CertificateID string
+				Subject       string
+			}{}
+			err := structFromParam(params[i], &certificate)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			_, err = onvifClient.CreateCertificate(certificate.CertificateID, certificate.Subject)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifLoadCertificates":
+			var certificates []struct {
+				CertificateID string
+				Certificate   string
+			}
+			err := structFromParam(params[i], &certificates)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			var nvtCertificates []tds.Certificate
+			for _, cert := range certificates {
+				data, err := encodeCertificate(cert.Certificate)
+				if err != nil {
+					d.lc.Error(err.Error())
+					return err
+				}
+				nvtCertificates = append(nvtCertificates, tds.Certificate{
+					CertificateID: cert.CertificateID,
+					Certificate:   tds.BinaryData{Data: data},
+				})
+			}
+
+			err = onvifClient.LoadCertificates(nvtCertificates)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifCertificatesStatus":
+			var status []struct {
+				CertificateID string
+				Status        bool
+			}
+			err := structFromParam(params[i], &status)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			var certificatesStatus []onvif.CertificateStatus
+			for _, s := range status {
+				certificatesStatus = append(certificatesStatus, onvif.CertificateStatus{
+					CertificateID: xsd.Token(s.CertificateID),
+					Status:        s.Status,
+				})
+			}
+
+			err = onvifClient.SetCertificatesStatus(certificatesStatus)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifHTTPSCertificate":
+			var cert httpsCertificate
+			err := structFromParam(params[i], &cert)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			ca, err := GetCertificateAuthority(d.config.CertificateAuthorityPath)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			err = onvifClient.EnableHTTPS(ca, cert)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifAudioOutputConfigurations":
+			config := struct {
+				Token       string
+				Name        string
+				OutputToken string
+				SendPrimacy string
+				OutputLevel int
+			}{}
+
+			err := structFromParam(params[i], &config)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			outputConfig := onvif.AudioOutputConfiguration{
+				ConfigurationEntity: onvif.ConfigurationEntity{
+					Token: onvif.ReferenceToken(config.Token),
+					Name:  onvif.Name(config.Name),
+				},
+				OutputToken: onvif.ReferenceToken(config.OutputToken),
+				SendPrimacy: xsd.AnyURI(config.SendPrimacy),
+				OutputLevel: config.OutputLevel,
+			}
+
+			err = onvifClient.SetAudioOutputConfiguration(outputConfig)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifAudioDecoderConfigurations":
+			config := struct {
+				Token string
+				Name  string
+			}{}
+
+			err := structFromParam(params[i], &config)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			decoderConfig := onvif.AudioDecoderConfiguration{
+				ConfigurationEntity: onvif.ConfigurationEntity{
+					Token: onvif.ReferenceToken(config.Token),
+					Name:  onvif.Name(config.Name),
+				},
+			}
+
+			err = onvifClient.SetAudioDecoderConfiguration(decoderConfig)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifAudioClip":
+			clip, err := params[i].StringValue()
+			if err != nil {
+				err := errors.New("non-string value passed to OnvifAudioClip command")
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			audio, err := d.loadAudioClip(clip)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			err = onvifClient.PlayAudio(audio)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "OnvifThermalConfiguration":
+			var config thermalConfiguration
+			err := structFromParam(params[i], &config)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+			err = onvifClient.SetThermalConfiguration(config)
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
+		case "MotionRegions":
+			err := d.setMotionRegions(onvifClient, c, req, params[i])
+			if err != nil {
+				d.lc.Error(err.Error())
+				return err
+			}
+
 		default:
 			if c == nil {
 				err := errors.New("non-onvif command for camera without secondary client")
@@ -353,6 +725,52 @@ func (d *Driver) HandleWriteCommands(deviceName string, protocols map[string]mod
 	return nil
 }
 
+// loadAudioClip reads a G.711 µ-law clip by file name from the configured AudioClipDirectory
+func (d *Driver) loadAudioClip(clip string) ([]byte, error) {
+	if clip == "" || clip != filepath.Base(clip) || clip == ".." {
+		return nil, fmt.Errorf("invalid audio clip name '%s'", clip)
+	}
+
+	return rtsp.LoadPCMU(filepath.Join(d.config.AudioClipDirectory, clip))
+}
+
+// getMotionRegions reads the motion regions through the camera's proprietary API where the
+// secondary client supports it, and through the ONVIF Analytics service otherwise
+func (d *Driver) getMotionRegions(onvifClient *OnvifClient, c client.Client, req sdkModel.CommandRequest) (string, error) {
+	if mrc, ok := c.(client.MotionRegionClient); ok {
+		regions, err := mrc.GetMotionRegions(req)
+		if !errors.Is(err, client.ErrUnsupported) {
+			return regions, err
+		}
+	}
+
+	return onvifClient.GetMotionRegions()
+}
+
+// setMotionRegions writes the motion regions through the camera's proprietary API where the
+// secondary client supports it, and through the ONVIF Analytics service otherwise
+func (d *Driver) setMotionRegions(onvifClient *OnvifClient, c client.Client, req sdkModel.CommandRequest, param *sdkModel.CommandValue) error {
+	if mrc, ok := c.(client.MotionRegionClient); ok {
+		regions, err := param.StringValue()
+		if err != nil {
+			return errors.New("non-string value passed to MotionRegions command")
+		}
+
+		err = mrc.SetMotionRegions(req, regions)
+		if !errors.Is(err, client.ErrUnsupported) {
+			return err
+		}
+	}
+
+	var regions motionRegions
+	err := structFromParam(param, &regions)
+	if err != nil {
+		return err
+	}
+
+	return onvifClient.SetMotionRegions(regions)
+}
+
 type stringer interface {
 	StringValue() (string, error)
 }
@@ -435,10 +853,27 @@ func (d *Driver) Initialize(lc logger.LoggingClient, asyncCh chan<- *sdkModel.As
 // for closing any in-use channels, including the channel used to send async
 // readings (if supported).
 func (d *Driver) Stop(force bool) error {
+	// the clients are released outside the lock, as releasing them may wait for their listeners
+	lock.Lock()
+	var releasing []client.Client
 	for _, c := range clients {
+		releasing = append(releasing, c)
+	}
+	var stopping []*OnvifClient
+	for _, c := range onvifClients {
+		stopping = append(stopping, c)
+	}
+	lock.Unlock()
+
+	for _, c := range releasing {
 		c.CameraRelease(force)
 	}
 
+	for _, c := range stopping {
+		c.StopEvents(force)
+		c.StopMetadata(force)
+	}
+
 	close(d.asynchCh)
 
 	return nil
@@ -495,7 +930,7 @@ func newClient(device models.Device, user string, password string) client.Client
 	if in("bosch", labels) {
 		c = initializeClient(device, profile, user, password)
 	} else if in("hanwha", labels) {
-		// c = initializeHanwhaClient(device, user, password)
+		c = initializeHanwhaClient(device, profile, user, password)
 	} else if in("axis", labels) {
 		c = initializeAxisClient(device, profile, user, password)
 	} else {
@@ -524,11 +959,24 @@ func initializeOnvifClient(device models.Device, user string, password string, a
 
 	// go to secretstore with credential path to get username and password
 	c := NewOnvifClient(addr, user, password, authMethod, driver.lc)
-	if c != nil {
-		// Only add the ONVIF client if it could be initialized. if it's offline then we might try again in an autoevent
-		lock.Lock()
-		onvifClients[addr] = c
+	if c == nil {
+		return nil
+	}
+
+	// Only add the ONVIF client if it could be initialized. if it's offline then we might try again in an autoevent
+	lock.Lock()
+	if existing, ok := onvifClients[addr]; ok {
 		lock.Unlock()
+		// a concurrent request added the client first, and started its listeners
+		return existing
+	}
+	onvifClients[addr] = c
+	lock.Unlock()
+
+	profile, err := sdk.RunningService().GetProfileByName(device.ProfileName)
+	if err == nil {
+		c.StartEvents(device, profile, driver.asynchCh)
+		c.StartMetadata(device, profile, driver.asynchCh)
 	}
 	return c
 }
@@ -559,25 +1007,48 @@ func initializeAxisClient(device models.Device, profile models.DeviceProfile, us
 	return c
 }
 
+func initializeHanwhaClient(device models.Device, profile models.DeviceProfile, user string, password string) client.Client {
+	addr := device.Protocols[HTTP_PROTOCOL][ADDRESS]
+
+	c := hanwha.NewClient(driver.asynchCh, driver.lc)
+	c.CameraInit(device, profile, addr, user, password)
+
+	lock.Lock()
+	clients[addr] = c
+	lock.Unlock()
+
+	return c
+}
+
 func initializeNoopClient() client.Client {
 	c := noop.NewClient()
 	return c
 }
 
+// shutdownOnvifClient removes the ONVIF client of the address and stops its listeners, outside the
+// lock as stopping them may wait for up to client.ForceStopTimeout
 func shutdownOnvifClient(addr string) {
-	// nothing much to do here at the moment
 	lock.Lock()
+	c, ok := onvifClients[addr]
 	delete(onvifClients, addr)
 	lock.Unlock()
+
+	if ok {
+		c.StopEvents(true)
+		c.StopMetadata(true)
+	}
 }
 
+// shutdownClient removes the client of the address and releases it outside the lock
 func shutdownClient(addr string) {
 	lock.Lock()
-
-	clients[addr].CameraRelease(true)
+	c, ok := clients[addr]
 	delete(clients, addr)
-
 	lock.Unlock()
+
+	if ok {
+		c.CameraRelease(true)
+	}
 }
 
 func in(needle string, haystack []string) bool {
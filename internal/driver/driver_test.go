@@ -16,12 +16,26 @@
 package driver
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"encoding/xml"
 	"errors"
+	"math/big"
+	"net"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/faceterteam/onvif4go/onvif"
+	"github.com/faceterteam/onvif4go/xsd"
 )
 
 type testStringer struct {
@@ -218,3 +232,244 @@ func TestStructFromParam(t *testing.T) {
 		})
 	}
 }
+
+func TestEventItemValue(t *testing.T) {
+	reading := func(itemID string, max string) xsd.AnyType {
+		return xsd.AnyType{Attrs: []xml.Attr{
+			{Name: xml.Name{Local: "ItemID"}, Value: itemID},
+			{Name: xml.Name{Local: "MaxTemperature"}, Value: max},
+		}}
+	}
+	message := onvif.Message{
+		Source: &onvif.ItemList{SimpleItems: map[string]string{"VideoSourceConfigurationToken": "vsc1"}},
+		Data: &onvif.ItemList{
+			SimpleItems:  map[string]string{"IsAlarm": "true"},
+			ElementItems: map[string][]xsd.AnyType{"Reading": {reading("box1", "35.5"), reading("box2", "80.25")}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		item          string
+		source        string
+		expectedValue string
+		expectedFound bool
+	}{
+		{"simple item", "IsAlarm", "", "true", true},
+		{"simple item with source", "IsAlarm", "vsc1", "true", true},
+		{"simple item with other source", "IsAlarm", "vsc2", "", false},
+		{"missing simple item", "IsFire", "", "", false},
+		{"first element attribute", "Reading/MaxTemperature", "", "35.5", true},
+		{"element attribute by item id", "Reading/MaxTemperature", "box2", "80.25", true},
+		{"element attribute with unknown item id", "Reading/MaxTemperature", "box3", "", false},
+		{"missing element attribute", "Reading/MinTemperature", "", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, found := eventItemValue(message, test.item, test.source)
+			if value != test.expectedValue || found != test.expectedFound {
+				t.Errorf("Expected: '%v' %v, Result: '%v' %v", test.expectedValue, test.expectedFound, value, found)
+			}
+		})
+	}
+}
+
+func TestCertificateAuthoritySign(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Camera CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caKeyDER, err := x509.MarshalECPrivateKey(caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca, err := parseCertificateAuthority(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: caKeyDER}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cameraKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "camera001"}}, cameraKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a request for names and addresses other than the camera's must not get them signed
+	foreignCSR, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:     pkix.Name{CommonName: "bank.example.com"},
+		DNSNames:    []string{"bank.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+	}, cameraKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		csr           []byte
+		host          string
+		expectedError bool
+	}{
+		{"IP address", csr, "192.168.2.105", false},
+		{"host name", csr, "camera001.local", false},
+		{"foreign names", foreignCSR, "192.168.2.105", false},
+		{"invalid request", []byte("ipsum lorum"), "192.168.2.105", true},
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.certificate)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			der, err := ca.sign(test.csr, test.host)
+			if (err != nil) != test.expectedError {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			if test.expectedError {
+				return
+			}
+
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cert.Subject.CommonName != test.host {
+				t.Errorf("Expected subject '%v', Result: '%v'", test.host, cert.Subject.CommonName)
+			}
+			if ip := net.ParseIP(test.host); ip != nil {
+				if len(cert.DNSNames) != 0 || len(cert.IPAddresses) != 1 || !cert.IPAddresses[0].Equal(ip) {
+					t.Errorf("Expected only address '%v', Result: %v %v", test.host, cert.DNSNames, cert.IPAddresses)
+				}
+			} else if len(cert.IPAddresses) != 0 || len(cert.DNSNames) != 1 || cert.DNSNames[0] != test.host {
+				t.Errorf("Expected only host name '%v', Result: %v %v", test.host, cert.DNSNames, cert.IPAddresses)
+			}
+			if cert.NotAfter.After(ca.certificate.NotAfter) {
+				t.Errorf("Certificate valid until %v, after the CA's %v", cert.NotAfter, ca.certificate.NotAfter)
+			}
+			_, err = cert.Verify(x509.VerifyOptions{DNSName: test.host, Roots: roots})
+			if err != nil {
+				t.Errorf("Signed certificate doesn't verify: %v", err)
+			}
+		})
+	}
+}
+
+func TestMetadataCommandValue(t *testing.T) {
+	document := `<?xml version="1.0" encoding="UTF-8"?>
+<tt:MetadataStream xmlns:tt="http://www.onvif.org/ver10/schema">
+  <tt:VideoAnalytics>
+    <tt:Frame UtcTime="2021-06-01T12:00:00.040Z">
+      <tt:Object ObjectId="12">
+        <tt:Appearance>
+          <tt:Shape>
+            <tt:BoundingBox left="-0.5" top="0.5" right="-0.25" bottom="0.1"/>
+            <tt:CenterOfGravity x="-0.375" y="0.3"/>
+          </tt:Shape>
+          <tt:Class>
+            <tt:ClassCandidate><tt:Type>Vehicle</tt:Type><tt:Likelihood>0.2</tt:Likelihood></tt:ClassCandidate>
+            <tt:ClassCandidate><tt:Type>Human</tt:Type><tt:Likelihood>0.8</tt:Likelihood></tt:ClassCandidate>
+          </tt:Class>
+        </tt:Appearance>
+        <tt:Behaviour><tt:Speed>1.5</tt:Speed></tt:Behaviour>
+      </tt:Object>
+      <tt:Object ObjectId="13">
+        <tt:Appearance>
+          <tt:Class><tt:Type Likelihood="0.9">Vehicle</tt:Type></tt:Class>
+        </tt:Appearance>
+      </tt:Object>
+    </tt:Frame>
+  </tt:VideoAnalytics>
+</tt:MetadataStream>`
+
+	frames, err := parseMetadataFrames([]byte(document))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		valueType     string
+		class         string
+		expectedFound bool
+		expectedValue string
+	}{
+		{
+			name:          "all objects",
+			valueType:     common.ValueTypeString,
+			expectedFound: true,
+			expectedValue: `[{"utcTime":"2021-06-01T12:00:00.040Z","objects":[` +
+				`{"objectId":"12","class":"Human","likelihood":0.8,"boundingBox":{"left":-0.5,"top":0.5,"right":-0.25,"bottom":0.1},"centerOfGravity":{"x":-0.375,"y":0.3},"speed":1.5},` +
+				`{"objectId":"13","class":"Vehicle","likelihood":0.9}]}]`,
+		},
+		{
+			name:          "vehicles",
+			valueType:     common.ValueTypeString,
+			class:         "vehicle",
+			expectedFound: true,
+			expectedValue: `[{"utcTime":"2021-06-01T12:00:00.040Z","objects":[{"objectId":"13","class":"Vehicle","likelihood":0.9}]}]`,
+		},
+		{
+			name:          "vehicles as object",
+			valueType:     common.ValueTypeObject,
+			class:         "Vehicle",
+			expectedFound: true,
+			expectedValue: `[{"utcTime":"2021-06-01T12:00:00.040Z","objects":[{"objectId":"13","class":"Vehicle","likelihood":0.9}]}]`,
+		},
+		{
+			name:      "no faces",
+			valueType: common.ValueTypeString,
+			class:     "Face",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mr := metadataResource{
+				resource: models.DeviceResource{Name: "VcaObjects", Properties: models.ResourceProperties{ValueType: test.valueType}},
+				class:    test.class,
+			}
+			cv, found, err := metadataCommandValue(mr, frames)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if found != test.expectedFound {
+				t.Fatalf("Expected found %v, Result: %v", test.expectedFound, found)
+			}
+			if !found {
+				return
+			}
+
+			value := cv.Value
+			if test.valueType == common.ValueTypeObject {
+				b, err := json.Marshal(cv.Value)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				value = string(b)
+			}
+			if value != test.expectedValue {
+				t.Errorf("Expected: %v, Result: %v", test.expectedValue, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package driver
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	sdk "github.com/edgexfoundry/device-sdk-go/v2/pkg/service"
+	"github.com/faceterteam/onvif4go/device"
+	"github.com/faceterteam/onvif4go/onvif"
+	"github.com/faceterteam/onvif4go/xsd"
+
+	tds "github.com/edgexfoundry/device-camera-go/internal/pkg/onvif/device"
+)
+
+const (
+	// keys of the CA certificate and private key, both PEM encoded, at CertificateAuthorityPath
+	caCertificateKey = "certificate"
+	caPrivateKeyKey  = "privateKey"
+
+	certificateValidity = 365 * 24 * time.Hour
+	defaultHTTPSPort    = 443
+	httpsProtocol       = "HTTPS"
+)
+
+// httpsCertificate is the certificate the OnvifHTTPSCertificate command has the camera create,
+// and the CA sign, for HTTPS
+type httpsCertificate struct {
+	CertificateID string
+	Subject       string
+	Port          int
+}
+
+// certificateAuthority signs the certificate requests of cameras
+type certificateAuthority struct {
+	certificate *x509.Certificate
+	key         crypto.Signer
+}
+
+// GetCertificateAuthority retrieves the CA which signs camera certificates from the SecretProvider
+func GetCertificateAuthority(secretPath string) (*certificateAuthority, error) {
+	if secretPath == "" {
+		return nil, errors.New("no CertificateAuthorityPath configured")
+	}
+
+	secretData, err := sdk.RunningService().SecretProvider.GetSecret(secretPath, caCertificateKey, caPrivateKeyKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve CA from SecretProvider at path '%s': %w", secretPath, err)
+	}
+
+	return parseCertificateAuthority([]byte(secretData[caCertificateKey]), []byte(secretData[caPrivateKeyKey]))
+}
+
+func parseCertificateAuthority(certPEM []byte, keyPEM []byte) (*certificateAuthority, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, errors.New("CA certificate is not PEM encoded")
+	}
+	certificate, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate: %w", err)
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, errors.New("CA private key is not PEM encoded")
+	}
+
+	var key interface{}
+	switch keyBlock.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(keyBlock.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("CA private key cannot sign certificates")
+	}
+
+	return &certificateAuthority{certificate: certificate, key: signer}, nil
+}
+
+// sign issues a server certificate for a DER encoded PKCS#10 request of the camera at host,
+// returning the DER encoded certificate.  The certificate is only valid for host: the names and
+// addresses the request asks for are chosen by the camera, so they are not trusted.
+func (ca *certificateAuthority) sign(csrDER []byte, host string) ([]byte, error) {
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate request: %w", err)
+	}
+	err = csr.CheckSignature()
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate request signature: %w", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(certificateValidity)
+	if notAfter.After(ca.certificate.NotAfter) {
+		notAfter = ca.certificate.NotAfter
+	}
+
+	// the subject's common name is replaced too, for clients still matching host names against it
+	subject := csr.Subject
+	subject.CommonName = host
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      subject,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	// clients connect to the camera by the address it was added with
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+
+	return x509.CreateCertificate(rand.Reader, &template, ca.certificate, csr.PublicKey, ca.key)
+}
+
+// GetCertificates returns the results of the ONVIF GetCertificates command
+func (c *OnvifClient) GetCertificates() (string, error) {
+	var res device.GetCertificatesResponse
+	err := c.onvifDevice.Call(device.GetCertificates{}, &res)
+	if err != nil {
+		return "", err
+	}
+
+	certificatesJSON, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+
+	return string(certificatesJSON), nil
+}
+
+// CreateCertificate has the camera generate a key pair and self-signed certificate, returning
+// the ID of the certificate, which the camera assigns when certificateID is empty
+func (c *OnvifClient) CreateCertificate(certificateID string, subject string) (string, error) {
+	req := device.CreateCertificate{}
+	if certificateID != "" {
+		id := xsd.Token(certificateID)
+		req.CertificateID = &id
+	}
+	if subject != "" {
+		req.Subject = &subject
+	}
+
+	var res device.CreateCertificateResponse
+	err := c.onvifDevice.Call(req, &res)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res.NvtCertificate.CertificateID), nil
+}
+
+// LoadCertificates uploads certificates for key pairs previously created by CreateCertificate
+func (c *OnvifClient) LoadCertificates(certificates []tds.Certificate) error {
+	var res tds.LoadCertificatesResponse
+	return c.onvifDevice.Call(tds.LoadCertificates{NVTCertificate: certificates}, &res)
+}
+
+// GetCertificatesStatus returns the results of the ONVIF GetCertificatesStatus command
+func (c *OnvifClient) GetCertificatesStatus() (string, error) {
+	var res device.GetCertificatesStatusResponse
+	err := c.onvifDevice.Call(device.GetCertificatesStatus{}, &res)
+	if err != nil {
+		return "", err
+	}
+
+	statusJSON, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+
+	return string(statusJSON), nil
+}
+
+// SetCertificatesStatus enables or disables certificates of the camera
+func (c *OnvifClient) SetCertificatesStatus(status []onvif.CertificateStatus) error {
+	var res device.SetCertificatesStatusResponse
+	return c.onvifDevice.Call(device.SetCertificatesStatus{CertificateStatus: status}, &res)
+}
+
+// SetNetworkProtocols enables or disables the camera's network protocols, such as HTTPS
+func (c *OnvifClient) SetNetworkProtocols(protocols []onvif.NetworkProtocol) error {
+	var res device.SetNetworkProtocolsResponse
+	return c.onvifDevice.Call(device.SetNetworkProtocols{NetworkProtocols: protocols}, &res)
+}
+
+// getPkcs10Request returns the DER encoded certificate request for the key pair of a certificate
+func (c *OnvifClient) getPkcs10Request(certificateID string, subject string) ([]byte, error) {
+	req := device.GetPkcs10Request{CertificateID: xsd.Token(certificateID)}
+	if subject != "" {
+		req.Subject = &subject
+	}
+
+	var res device.GetPkcs10RequestResponse
+	err := c.onvifDevice.Call(req, &res)
+	if err != nil {
+		return nil, fmt.Errorf("GetPkcs10Request failed: %v", err.Error())
+	}
+
+	return decodeBinaryData(string(res.Pkcs10Request.Data))
+}
+
+// EnableHTTPS has the camera create a key pair, signs its certificate request with the CA and
+// uploads the certificate, which then becomes the camera's only enabled certificate before HTTPS
+// is enabled
+func (c *OnvifClient) EnableHTTPS(ca *certificateAuthority, cert httpsCertificate) error {
+	certificateID, err := c.CreateCertificate(cert.CertificateID, cert.Subject)
+	if err != nil {
+		return fmt.Errorf("CreateCertificate failed: %v", err.Error())
+	}
+
+	csr, err := c.getPkcs10Request(certificateID, cert.Subject)
+	if err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(c.ipAddress)
+	if err != nil {
+		host = c.ipAddress
+	}
+	signed, err := ca.sign(csr, host)
+	if err != nil {
+		return err
+	}
+
+	err = c.LoadCertificates([]tds.Certificate{{
+		CertificateID: certificateID,
+		Certificate:   tds.BinaryData{Data: base64.StdEncoding.EncodeToString(signed)},
+	}})
+	if err != nil {
+		return fmt.Errorf("LoadCertificates failed: %v", err.Error())
+	}
+
+	var statusResp device.GetCertificatesStatusResponse
+	err = c.onvifDevice.Call(device.GetCertificatesStatus{}, &statusResp)
+	if err != nil {
+		return fmt.Errorf("GetCertificatesStatus failed: %v", err.Error())
+	}
+	status := []onvif.CertificateStatus{{CertificateID: xsd.Token(certificateID), Status: true}}
+	for _, s := range statusResp.CertificateStatus {
+		if string(s.CertificateID) != certificateID {
+			status = append(status, onvif.CertificateStatus{CertificateID: s.CertificateID, Status: false})
+		}
+	}
+	err = c.SetCertificatesStatus(status)
+	if err != nil {
+		return fmt.Errorf("SetCertificatesStatus failed: %v", err.Error())
+	}
+
+	port := cert.Port
+	if port == 0 {
+		port = defaultHTTPSPort
+	}
+	err = c.SetNetworkProtocols([]onvif.NetworkProtocol{{Name: httpsProtocol, Enabled: true, Port: port}})
+	if err != nil {
+		return fmt.Errorf("SetNetworkProtocols failed: %v", err.Error())
+	}
+
+	return nil
+}
+
+// decodeBinaryData decodes the base64 content of ONVIF binary data, which some cameras wrap
+func decodeBinaryData(data string) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(data), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 binary data: %w", err)
+	}
+
+	// cameras may return PEM rather than DER
+	if block, _ := pem.Decode(decoded); block != nil {
+		return block.Bytes, nil
+	}
+	return decoded, nil
+}
+
+// encodeCertificate returns the base64 DER content of a PEM or base64 DER encoded certificate
+func encodeCertificate(certificate string) (string, error) {
+	der, err := decodeBinaryData(certificate)
+	if block, _ := pem.Decode([]byte(certificate)); block != nil {
+		der, err = block.Bytes, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, err = x509.ParseCertificate(der)
+	if err != nil {
+		return "", fmt.Errorf("invalid certificate: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(der), nil
+}
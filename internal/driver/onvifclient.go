@@ -5,16 +5,43 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
 	"github.com/faceterteam/onvif4go"
 	"github.com/faceterteam/onvif4go/device"
+	"github.com/faceterteam/onvif4go/media"
 	"github.com/faceterteam/onvif4go/onvif"
+	"github.com/faceterteam/onvif4go/soap"
 
 	"github.com/edgexfoundry/device-camera-go/internal/pkg/digest"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/onvif/analytics"
+	tds "github.com/edgexfoundry/device-camera-go/internal/pkg/onvif/device"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/onvif/thermal"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/rtsp"
 )
 
+const (
+	cellMotionEngine   = "CellMotionEngine"
+	cellMotionDetector = "CellMotionDetector"
+)
+
+// thermalConfiguration is the thermal configuration of a video source
+type thermalConfiguration struct {
+	VideoSourceToken string
+	Configuration    thermal.Configuration
+}
+
+// motionRegions holds the cell motion detection modules and rules of a video analytics configuration
+type motionRegions struct {
+	ConfigurationToken string
+	Modules            []analytics.Config
+	Rules              []analytics.Config
+}
+
 // OnvifClient manages the state required to issue ONVIF requests to a camera
 type OnvifClient struct {
 	ipAddress    string
@@ -24,16 +51,27 @@ type OnvifClient struct {
 	onvifDevice  *onvif4go.OnvifDevice
 	lc           logger.LoggingClient
 	digestClient digest.Client
+
+	// timeDiff is the offset of the camera's clock, needed for WS-Security on services called via callService
+	timeDiff time.Duration
+
+	// serviceAddrs caches the addresses of the services called via callService by namespace
+	serviceAddrs map[string]string
+	serviceLock  sync.Mutex
+
+	events   *eventListener
+	metadata *metadataListener
 }
 
 // NewOnvifClient returns an OnvifClient for a single camera
 func NewOnvifClient(ipAddress string, user string, password string, cameraAuth string, lc logger.LoggingClient) *OnvifClient {
 	c := OnvifClient{
-		ipAddress:  ipAddress,
-		user:       user,
-		password:   password,
-		cameraAuth: cameraAuth,
-		lc:         lc,
+		ipAddress:    ipAddress,
+		user:         user,
+		password:     password,
+		cameraAuth:   cameraAuth,
+		lc:           lc,
+		serviceAddrs: make(map[string]string),
 	}
 
 	dev := onvif4go.NewOnvifDevice(c.ipAddress)
@@ -55,6 +93,12 @@ func NewOnvifClient(ipAddress string, user string, password string, cameraAuth s
 
 	c.onvifDevice = dev
 
+	dateTimeResp, err := dev.Device.GetSystemDateAndTime()
+	if err == nil {
+		deviceTime, _ := dateTimeResp.SystemDateAndTime.GetUTCTime()
+		c.timeDiff = deviceTime.Sub(time.Now().UTC())
+	}
+
 	c.digestClient = digest.NewDClient(&http.Client{}, user, password)
 	return &c
 }
@@ -338,3 +382,460 @@ func (c *OnvifClient) CreateUser(user onvif.User) error {
 	err := c.onvifDevice.Device.CreateUser(user)
 	return err
 }
+
+// GetRemoteUser returns the results of the ONVIF GetRemoteUser command
+func (c *OnvifClient) GetRemoteUser() (string, error) {
+	remoteUser, err := c.onvifDevice.Device.GetRemoteUser()
+	if err != nil {
+		return "", err
+	}
+
+	remoteUserJSON, err := json.Marshal(remoteUser)
+	if err != nil {
+		return "", err
+	}
+
+	return string(remoteUserJSON), nil
+}
+
+// SetRemoteUser sets the user the camera uses to access other devices, or removes it if nil
+func (c *OnvifClient) SetRemoteUser(remoteUser *onvif.RemoteUser) error {
+	return c.onvifDevice.Device.SetRemoteUser(remoteUser)
+}
+
+// GetWsdlUrl returns the results of the ONVIF GetWsdlUrl command
+func (c *OnvifClient) GetWsdlUrl() (string, error) {
+	wsdlURL, err := c.onvifDevice.Device.GetWsdlUrl()
+	if err != nil {
+		return "", err
+	}
+
+	wsdlURLJSON, err := json.Marshal(wsdlURL)
+	if err != nil {
+		return "", err
+	}
+
+	return string(wsdlURLJSON), nil
+}
+
+// GetEndpointReference returns the results of the ONVIF GetEndpointReference command
+func (c *OnvifClient) GetEndpointReference() (string, error) {
+	reference, err := c.onvifDevice.Device.GetEndpointReference()
+	if err != nil {
+		return "", err
+	}
+
+	referenceJSON, err := json.Marshal(reference)
+	if err != nil {
+		return "", err
+	}
+
+	return string(referenceJSON), nil
+}
+
+// GetAccessPolicy returns the camera's access policy file, with its content base64 encoded
+func (c *OnvifClient) GetAccessPolicy() (string, error) {
+	err := c.requireSecurityCapability(func(caps onvif.DeviceCapabilities) bool {
+		return caps.Security.AccessPolicyConfig
+	}, "access policy configuration")
+	if err != nil {
+		return "", err
+	}
+
+	var res tds.GetAccessPolicyResponse
+	err = c.onvifDevice.Call(tds.GetAccessPolicy{}, &res)
+	if err != nil {
+		return "", err
+	}
+
+	policyJSON, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+
+	return string(policyJSON), nil
+}
+
+// SetAccessPolicy replaces the camera's access policy file
+func (c *OnvifClient) SetAccessPolicy(policy tds.BinaryData) error {
+	err := c.requireSecurityCapability(func(caps onvif.DeviceCapabilities) bool {
+		return caps.Security.AccessPolicyConfig
+	}, "access policy configuration")
+	if err != nil {
+		return err
+	}
+
+	var res tds.SetAccessPolicyResponse
+	return c.onvifDevice.Call(tds.SetAccessPolicy{PolicyFile: policy}, &res)
+}
+
+// GetIPAddressFilter returns the results of the ONVIF GetIPAddressFilter command
+func (c *OnvifClient) GetIPAddressFilter() (string, error) {
+	err := c.requireSecurityCapability(func(caps onvif.DeviceCapabilities) bool {
+		return caps.Network.IPFilter
+	}, "IP address filtering")
+	if err != nil {
+		return "", err
+	}
+
+	var res tds.GetIPAddressFilterResponse
+	err = c.onvifDevice.Call(tds.GetIPAddressFilter{}, &res)
+	if err != nil {
+		return "", err
+	}
+
+	filterJSON, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+
+	return string(filterJSON), nil
+}
+
+// SetIPAddressFilter replaces the camera's IP address filter
+func (c *OnvifClient) SetIPAddressFilter(filter tds.IPAddressFilter) error {
+	var res tds.SetIPAddressFilterResponse
+	return c.callIPAddressFilter(tds.SetIPAddressFilter{IPAddressFilter: filter}, &res)
+}
+
+// AddIPAddressFilter adds addresses to the camera's IP address filter
+func (c *OnvifClient) AddIPAddressFilter(filter tds.IPAddressFilter) error {
+	var res tds.AddIPAddressFilterResponse
+	return c.callIPAddressFilter(tds.AddIPAddressFilter{IPAddressFilter: filter}, &res)
+}
+
+// RemoveIPAddressFilter removes addresses from the camera's IP address filter
+func (c *OnvifClient) RemoveIPAddressFilter(filter tds.IPAddressFilter) error {
+	var res tds.RemoveIPAddressFilterResponse
+	return c.callIPAddressFilter(tds.RemoveIPAddressFilter{IPAddressFilter: filter}, &res)
+}
+
+func (c *OnvifClient) callIPAddressFilter(request interface{}, response interface{}) error {
+	err := c.requireSecurityCapability(func(caps onvif.DeviceCapabilities) bool {
+		return caps.Network.IPFilter
+	}, "IP address filtering")
+	if err != nil {
+		return err
+	}
+
+	return c.onvifDevice.Call(request, response)
+}
+
+// requireSecurityCapability returns an error unless the camera reports a device capability, as
+// read by GetCapabilities when the device was initialized
+func (c *OnvifClient) requireSecurityCapability(supported func(onvif.DeviceCapabilities) bool, name string) error {
+	if c.onvifDevice.Capabilities.Device == nil || !supported(*c.onvifDevice.Capabilities.Device) {
+		return fmt.Errorf("camera does not support ONVIF %s", name)
+	}
+
+	return nil
+}
+
+// GetAudioOutputConfigurations returns the results of the ONVIF GetAudioOutputConfigurations command
+func (c *OnvifClient) GetAudioOutputConfigurations() (string, error) {
+	configs, err := c.onvifDevice.Media.GetAudioOutputConfigurations()
+	if err != nil {
+		return "", err
+	}
+
+	configsJSON, err := json.Marshal(configs)
+	if err != nil {
+		return "", err
+	}
+
+	return string(configsJSON), nil
+}
+
+// SetAudioOutputConfiguration modifies an audio output configuration via the ONVIF SetAudioOutputConfiguration command
+func (c *OnvifClient) SetAudioOutputConfiguration(config onvif.AudioOutputConfiguration) error {
+	var res media.SetAudioOutputConfigurationResponse
+	err := c.onvifDevice.Call(media.SetAudioOutputConfiguration{Configuration: config, ForcePersistence: true}, &res)
+	return err
+}
+
+// GetAudioDecoderConfigurations returns the results of the ONVIF GetAudioDecoderConfigurations command
+func (c *OnvifClient) GetAudioDecoderConfigurations() (string, error) {
+	configs, err := c.onvifDevice.Media.GetAudioDecoderConfigurations()
+	if err != nil {
+		return "", err
+	}
+
+	configsJSON, err := json.Marshal(configs)
+	if err != nil {
+		return "", err
+	}
+
+	return string(configsJSON), nil
+}
+
+// SetAudioDecoderConfiguration modifies an audio decoder configuration via the ONVIF SetAudioDecoderConfiguration command
+func (c *OnvifClient) SetAudioDecoderConfiguration(config onvif.AudioDecoderConfiguration) error {
+	var res media.SetAudioDecoderConfigurationResponse
+	err := c.onvifDevice.Call(media.SetAudioDecoderConfiguration{Configuration: config, ForcePersistence: true}, &res)
+	return err
+}
+
+// PlayAudio sends G.711 µ-law audio to the camera's speaker through the RTSP audio backchannel
+// of the first media profile with an audio output configuration
+func (c *OnvifClient) PlayAudio(audio []byte) error {
+	profilesResp, err := c.onvifDevice.Media.GetProfiles()
+	if err != nil {
+		return err
+	}
+
+	var token onvif.ReferenceToken
+	for _, profile := range profilesResp.Profiles {
+		if profile.Extension != nil && profile.Extension.AudioOutputConfiguration != nil {
+			token = profile.Token
+			break
+		}
+	}
+	if token == "" {
+		return fmt.Errorf("no onvif profile with an audio output configuration found")
+	}
+
+	uriResp, err := c.onvifDevice.Media.GetStreamURI(string(token), "RTP-Unicast", "RTSP")
+	if err != nil {
+		return fmt.Errorf("GetStreamURI failed: %v", err.Error())
+	}
+
+	rtspClient, err := rtsp.NewClient(string(uriResp.MediaUri.Uri), c.user, c.password)
+	if err != nil {
+		return err
+	}
+
+	return rtspClient.PlayBackchannel(audio)
+}
+
+// GetThermalConfiguration returns the thermal configuration of the first video source via the ONVIF Thermal service
+func (c *OnvifClient) GetThermalConfiguration() (string, error) {
+	token, err := c.getVideoSourceToken()
+	if err != nil {
+		return "", err
+	}
+
+	var res thermal.GetConfigurationResponse
+	err = c.callService(thermal.Namespace, thermal.GetConfiguration{VideoSourceToken: token}, &res)
+	if err != nil {
+		return "", fmt.Errorf("GetConfiguration failed: %v", err.Error())
+	}
+
+	configJSON, err := json.Marshal(thermalConfiguration{VideoSourceToken: token, Configuration: res.Configuration})
+	if err != nil {
+		return "", err
+	}
+
+	return string(configJSON), nil
+}
+
+// SetThermalConfiguration modifies the thermal configuration of a video source via the ONVIF Thermal service.
+// The first video source is modified if no VideoSourceToken is given.
+func (c *OnvifClient) SetThermalConfiguration(config thermalConfiguration) error {
+	token := config.VideoSourceToken
+	if token == "" {
+		var err error
+		token, err = c.getVideoSourceToken()
+		if err != nil {
+			return err
+		}
+	}
+
+	var res thermal.SetConfigurationResponse
+	err := c.callService(thermal.Namespace, thermal.SetConfiguration{VideoSourceToken: token, Configuration: config.Configuration}, &res)
+	if err != nil {
+		return fmt.Errorf("SetConfiguration failed: %v", err.Error())
+	}
+
+	return nil
+}
+
+// GetRadiometryConfiguration returns the radiometry configuration of the first video source via the ONVIF Thermal service
+func (c *OnvifClient) GetRadiometryConfiguration() (string, error) {
+	token, err := c.getVideoSourceToken()
+	if err != nil {
+		return "", err
+	}
+
+	var res thermal.GetRadiometryConfigurationResponse
+	err = c.callService(thermal.Namespace, thermal.GetRadiometryConfiguration{VideoSourceToken: token}, &res)
+	if err != nil {
+		return "", fmt.Errorf("GetRadiometryConfiguration failed: %v", err.Error())
+	}
+
+	configJSON, err := json.Marshal(res.Configuration)
+	if err != nil {
+		return "", err
+	}
+
+	return string(configJSON), nil
+}
+
+func (c *OnvifClient) getVideoSourceToken() (string, error) {
+	sourcesResp, err := c.onvifDevice.Media.GetVideoSources()
+	if err != nil {
+		return "", err
+	}
+
+	if len(sourcesResp.VideoSources) == 0 {
+		return "", fmt.Errorf("no onvif video sources found")
+	}
+
+	return string(sourcesResp.VideoSources[0].Token), nil
+}
+
+// callService sends a request to an ONVIF service for which onvif4go has no endpoint.  The
+// address of the service is looked up once, and again after a failed call.
+func (c *OnvifClient) callService(namespace string, request interface{}, response interface{}) error {
+	addr, err := c.serviceAddress(namespace)
+	if err != nil {
+		return err
+	}
+
+	var headers []interface{}
+	if c.cameraAuth != NO_AUTH {
+		headers = append(headers, soap.MakeWSSecurity(c.user, c.password, c.timeDiff))
+	}
+
+	err = soap.NewSoapClient(addr).Do(request, response, headers...)
+	if err != nil {
+		// the address may have changed, e.g. after a firmware update
+		c.serviceLock.Lock()
+		delete(c.serviceAddrs, namespace)
+		c.serviceLock.Unlock()
+	}
+	return err
+}
+
+// serviceAddress returns the address of the service with the given namespace, looking it up via
+// GetServices unless it is cached
+func (c *OnvifClient) serviceAddress(namespace string) (string, error) {
+	c.serviceLock.Lock()
+	defer c.serviceLock.Unlock()
+
+	if addr, ok := c.serviceAddrs[namespace]; ok {
+		return addr, nil
+	}
+
+	servicesResp, err := c.onvifDevice.Device.GetServices(false)
+	if err != nil {
+		return "", fmt.Errorf("GetServices failed: %v", err.Error())
+	}
+
+	var xaddr string
+	for _, service := range servicesResp.Service {
+		if string(service.Namespace) == namespace {
+			xaddr = string(service.XAddr)
+			break
+		}
+	}
+	if xaddr == "" {
+		return "", fmt.Errorf("camera does not provide the ONVIF service %s", namespace)
+	}
+
+	// cameras may report an address which isn't reachable from the device service, e.g. behind NAT
+	u, err := url.Parse(xaddr)
+	if err != nil {
+		return "", fmt.Errorf("invalid address for ONVIF service %s: %v", namespace, err.Error())
+	}
+	u.Host = c.ipAddress
+
+	c.serviceAddrs[namespace] = u.String()
+	return c.serviceAddrs[namespace], nil
+}
+
+// GetMotionRegions returns the CellMotionEngine modules and CellMotionDetector rules of the camera's
+// first video analytics configuration, as reported by the ONVIF Analytics service
+func (c *OnvifClient) GetMotionRegions() (string, error) {
+	token, err := c.getVideoAnalyticsToken()
+	if err != nil {
+		return "", err
+	}
+
+	var modulesResp analytics.GetAnalyticsModulesResponse
+	err = c.onvifDevice.Call(analytics.GetAnalyticsModules{ConfigurationToken: token}, &modulesResp)
+	if err != nil {
+		return "", fmt.Errorf("GetAnalyticsModules failed: %v", err.Error())
+	}
+
+	var rulesResp analytics.GetRulesResponse
+	err = c.onvifDevice.Call(analytics.GetRules{ConfigurationToken: token}, &rulesResp)
+	if err != nil {
+		return "", fmt.Errorf("GetRules failed: %v", err.Error())
+	}
+
+	regions := motionRegions{ConfigurationToken: token}
+	for _, module := range modulesResp.AnalyticsModule {
+		if isQName(module.Type, cellMotionEngine) {
+			regions.Modules = append(regions.Modules, module)
+		}
+	}
+	for _, rule := range rulesResp.Rule {
+		if isQName(rule.Type, cellMotionDetector) {
+			regions.Rules = append(regions.Rules, rule)
+		}
+	}
+
+	regionsJSON, err := json.Marshal(regions)
+	if err != nil {
+		return "", err
+	}
+
+	return string(regionsJSON), nil
+}
+
+// SetMotionRegions modifies the cell motion detection modules and rules via the ONVIF Analytics service.
+// The first video analytics configuration is modified if no ConfigurationToken is given.
+func (c *OnvifClient) SetMotionRegions(regions motionRegions) error {
+	token := regions.ConfigurationToken
+	if token == "" {
+		var err error
+		token, err = c.getVideoAnalyticsToken()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(regions.Modules) > 0 {
+		for i := range regions.Modules {
+			regions.Modules[i].Prefix = analytics.SchemaNamespace
+		}
+
+		var res analytics.ModifyAnalyticsModulesResponse
+		err := c.onvifDevice.Call(analytics.ModifyAnalyticsModules{ConfigurationToken: token, AnalyticsModule: regions.Modules}, &res)
+		if err != nil {
+			return fmt.Errorf("ModifyAnalyticsModules failed: %v", err.Error())
+		}
+	}
+
+	if len(regions.Rules) > 0 {
+		for i := range regions.Rules {
+			regions.Rules[i].Prefix = analytics.SchemaNamespace
+		}
+
+		var res analytics.ModifyRulesResponse
+		err := c.onvifDevice.Call(analytics.ModifyRules{ConfigurationToken: token, Rule: regions.Rules}, &res)
+		if err != nil {
+			return fmt.Errorf("ModifyRules failed: %v", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (c *OnvifClient) getVideoAnalyticsToken() (string, error) {
+	configsResp, err := c.onvifDevice.Media.GetVideoAnalyticsConfigurations()
+	if err != nil {
+		return "", err
+	}
+
+	if len(configsResp.Configurations) == 0 {
+		return "", fmt.Errorf("no onvif video analytics configurations found")
+	}
+
+	return string(configsResp.Configurations[0].Token), nil
+}
+
+// isQName reports whether a QName such as "tt:CellMotionDetector" has the given local name
+func isQName(qname string, local string) bool {
+	return qname == local || strings.HasSuffix(qname, ":"+local)
+}
@@ -0,0 +1,219 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/faceterteam/onvif4go"
+	"github.com/faceterteam/onvif4go/events"
+	"github.com/faceterteam/onvif4go/onvif"
+
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/client"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/eventtopic"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/reading"
+)
+
+const (
+	pullTimeout      = 10 * time.Second
+	pullMessageLimit = 32
+	pullRetryWait    = 5 * time.Second
+)
+
+var errEventsCancelled = errors.New("cancelled")
+
+// eventResource is a device resource whose readings come from ONVIF event notifications.  It is
+// configured by the resource attributes onvif_event_topic, e.g.
+// "tns1:VideoAnalytics/Radiometry/BoxTemperatureReading", and onvif_event_item, the name of the
+// message data item holding the value.  Values held in an attribute of an element item are
+// addressed as "<item>/<attribute>", e.g. "Reading/MaxTemperature".  The optional
+// onvif_event_source attribute selects messages by a source item value or by an attribute
+// value of the element item, e.g. the ItemID of a measurement box.
+type eventResource struct {
+	resource models.DeviceResource
+	topic    string
+	item     string
+	source   string
+}
+
+// eventListener pulls ONVIF event notifications from a camera and sends the values of the
+// mapped device resources as async readings
+type eventListener struct {
+	client    *OnvifClient
+	device    models.Device
+	resources []eventResource
+	asyncCh   chan<- *sdkModel.AsyncValues
+
+	stop    chan bool
+	stopped chan bool
+}
+
+func eventResourcesFromProfile(profile models.DeviceProfile) []eventResource {
+	var resources []eventResource
+	for _, dr := range profile.DeviceResources {
+		topic, ok := dr.Attributes["onvif_event_topic"].(string)
+		if !ok {
+			continue
+		}
+		item, _ := dr.Attributes["onvif_event_item"].(string)
+		source, _ := dr.Attributes["onvif_event_source"].(string)
+
+		resources = append(resources, eventResource{resource: dr, topic: topic, item: item, source: source})
+	}
+	return resources
+}
+
+// StartEvents subscribes to the camera's ONVIF events when the device profile maps any event
+// topics to device resources
+func (c *OnvifClient) StartEvents(device models.Device, profile models.DeviceProfile, asyncCh chan<- *sdkModel.AsyncValues) {
+	resources := eventResourcesFromProfile(profile)
+	if len(resources) == 0 {
+		return
+	}
+
+	if c.onvifDevice.Events == nil {
+		c.lc.Warnf("Device '%s' maps ONVIF events to resources, but the camera has no ONVIF event service", device.Name)
+		return
+	}
+
+	c.events = &eventListener{
+		client:    c,
+		device:    device,
+		resources: resources,
+		asyncCh:   asyncCh,
+		stop:      make(chan bool),
+		stopped:   make(chan bool),
+	}
+
+	go c.events.run()
+}
+
+// StopEvents ends the ONVIF event subscription, waiting for it to be torn down, or for at most
+// client.ForceStopTimeout when forced
+func (c *OnvifClient) StopEvents(force bool) {
+	if c.events == nil {
+		return
+	}
+
+	close(c.events.stop)
+	client.WaitStopped(c.events.stopped, force)
+	c.events = nil
+}
+
+func (l *eventListener) run() {
+	defer close(l.stopped)
+
+	for {
+		err := l.pullEvents()
+		if err == errEventsCancelled {
+			return
+		}
+		l.client.lc.Errorf("ONVIF event subscription for device '%s' failed: %s", l.device.Name, err.Error())
+
+		select {
+		case <-l.stop:
+			return
+		case <-time.After(pullRetryWait):
+		}
+	}
+}
+
+func (l *eventListener) pullEvents() error {
+	subscriptionResp, err := l.client.onvifDevice.Events.CreatePullPointSubscription("", false, nil)
+	if err != nil {
+		return fmt.Errorf("CreatePullPointSubscription failed: %v", err.Error())
+	}
+
+	subscription := onvif4go.NewPullPointSubscription(subscriptionResp, l.client.onvifDevice)
+	defer subscription.Unsubscribe() //nolint:errcheck
+
+	for {
+		select {
+		case <-l.stop:
+			return errEventsCancelled
+		default:
+		}
+
+		messagesResp, err := subscription.PullMessages(pullTimeout, pullMessageLimit)
+		if err != nil {
+			return fmt.Errorf("PullMessages failed: %v", err.Error())
+		}
+
+		cvs := l.commandValues(messagesResp.NotificationMessages)
+		if len(cvs) > 0 {
+			av := &sdkModel.AsyncValues{DeviceName: l.device.Name, CommandValues: cvs}
+			if !client.SendAsyncValues(l.asyncCh, l.stop, av) {
+				return errEventsCancelled
+			}
+		}
+	}
+}
+
+func (l *eventListener) commandValues(notifications []events.NotificationMessage) []*sdkModel.CommandValue {
+	var cvs []*sdkModel.CommandValue
+	for _, notification := range notifications {
+		for _, message := range notification.Message.Messages {
+			for _, er := range l.resources {
+				if !eventtopic.Matches(notification.Topic.Value, er.topic) || message.Data == nil {
+					continue
+				}
+				value, ok := eventItemValue(message, er.item, er.source)
+				if !ok {
+					continue
+				}
+
+				cv, err := reading.FromString(er.resource.Name, er.resource.Properties.ValueType, value, reading.Options{})
+				if err != nil {
+					l.client.lc.Warnf("Unable to convert ONVIF event item '%s' for resource '%s': %s", er.item, er.resource.Name, err.Error())
+					continue
+				}
+				cv.Origin = time.Now().UnixNano() / int64(time.Millisecond)
+				cvs = append(cvs, cv)
+			}
+		}
+	}
+	return cvs
+}
+
+// eventItemValue returns the value of a data item of the message, provided the message
+// matches the source, if one is given
+func eventItemValue(message onvif.Message, item string, source string) (string, bool) {
+	sourceMatched := source == ""
+	if !sourceMatched && message.Source != nil {
+		for _, v := range message.Source.SimpleItems {
+			if v == source {
+				sourceMatched = true
+			}
+		}
+	}
+
+	name, attr, isElement := strings.Cut(item, "/")
+	if !isElement {
+		value, ok := message.Data.SimpleItems[name]
+		if !ok || !sourceMatched {
+			return "", false
+		}
+		return value, true
+	}
+
+	for _, element := range message.Data.ElementItems[name] {
+		var value string
+		var found bool
+		elementMatched := sourceMatched
+		for _, a := range element.Attrs {
+			if a.Name.Local == attr {
+				value, found = a.Value, true
+			}
+			if a.Value == source {
+				elementMatched = true
+			}
+		}
+		if found && elementMatched {
+			return value, true
+		}
+	}
+	return "", false
+}
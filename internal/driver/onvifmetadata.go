@@ -0,0 +1,303 @@
+package driver
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/client"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/rtsp"
+)
+
+const metadataObjects = "objects"
+
+// metadataResource is a device resource whose readings are the objects of the camera's ONVIF
+// metadata stream, such as the objects tracked by Bosch IVA.  It is configured by the resource
+// attribute onvif_metadata: "objects" and the optional onvif_metadata_class, which limits the
+// objects to one class, e.g. "Human" or "Vehicle".  Resources of value type Object receive the
+// frames as an object, any other value type as JSON.
+type metadataResource struct {
+	resource models.DeviceResource
+	class    string
+}
+
+// metadataFrame is a video frame of the metadata stream and the objects detected in it
+type metadataFrame struct {
+	UtcTime string           `json:"utcTime"`
+	Objects []metadataObject `json:"objects"`
+}
+
+type metadataObject struct {
+	ObjectID        string       `json:"objectId"`
+	Class           string       `json:"class,omitempty"`
+	Likelihood      float64      `json:"likelihood,omitempty"`
+	BoundingBox     *boundingBox `json:"boundingBox,omitempty"`
+	CenterOfGravity *point       `json:"centerOfGravity,omitempty"`
+	Speed           *float64     `json:"speed,omitempty"`
+}
+
+// boundingBox and point are in the normalized coordinates of the frame, from -1 to 1
+type boundingBox struct {
+	Left   float64 `json:"left" xml:"left,attr"`
+	Top    float64 `json:"top" xml:"top,attr"`
+	Right  float64 `json:"right" xml:"right,attr"`
+	Bottom float64 `json:"bottom" xml:"bottom,attr"`
+}
+
+type point struct {
+	X float64 `json:"x" xml:"x,attr"`
+	Y float64 `json:"y" xml:"y,attr"`
+}
+
+// metadataStream is a tt:MetadataStream document.  Elements are matched by their local names
+// only, as cameras are free to choose namespace prefixes.
+type metadataStream struct {
+	Frames []struct {
+		UtcTime string `xml:"UtcTime,attr"`
+		Objects []struct {
+			ObjectID   string `xml:"ObjectId,attr"`
+			Appearance struct {
+				Shape struct {
+					BoundingBox     *boundingBox `xml:"BoundingBox"`
+					CenterOfGravity *point       `xml:"CenterOfGravity"`
+				} `xml:"Shape"`
+				Class struct {
+					// ONVIF 1.x lists candidates, later versions types with a Likelihood attribute
+					Candidates []struct {
+						Type       string  `xml:"Type"`
+						Likelihood float64 `xml:"Likelihood"`
+					} `xml:"ClassCandidate"`
+					Types []struct {
+						Type       string  `xml:",chardata"`
+						Likelihood float64 `xml:"Likelihood,attr"`
+					} `xml:"Type"`
+				} `xml:"Class"`
+			} `xml:"Appearance"`
+			Behaviour struct {
+				Speed *float64 `xml:"Speed"`
+			} `xml:"Behaviour"`
+		} `xml:"Object"`
+	} `xml:"VideoAnalytics>Frame"`
+}
+
+// metadataListener receives the ONVIF metadata stream of a camera and sends the objects of each
+// document as async readings of the mapped device resources
+type metadataListener struct {
+	client    *OnvifClient
+	device    models.Device
+	resources []metadataResource
+	asyncCh   chan<- *sdkModel.AsyncValues
+
+	stop    chan bool
+	stopped chan bool
+}
+
+func metadataResourcesFromProfile(profile models.DeviceProfile) []metadataResource {
+	var resources []metadataResource
+	for _, dr := range profile.DeviceResources {
+		if metadata, ok := dr.Attributes["onvif_metadata"].(string); !ok || metadata != metadataObjects {
+			continue
+		}
+		class, _ := dr.Attributes["onvif_metadata_class"].(string)
+
+		resources = append(resources, metadataResource{resource: dr, class: class})
+	}
+	return resources
+}
+
+// StartMetadata receives the camera's ONVIF metadata stream when the device profile maps its
+// objects to any device resources
+func (c *OnvifClient) StartMetadata(device models.Device, profile models.DeviceProfile, asyncCh chan<- *sdkModel.AsyncValues) {
+	resources := metadataResourcesFromProfile(profile)
+	if len(resources) == 0 {
+		return
+	}
+
+	c.metadata = &metadataListener{
+		client:    c,
+		device:    device,
+		resources: resources,
+		asyncCh:   asyncCh,
+		stop:      make(chan bool),
+		stopped:   make(chan bool),
+	}
+
+	go c.metadata.run()
+}
+
+// StopMetadata ends the metadata stream, waiting for the RTSP session to be torn down, or for at
+// most client.ForceStopTimeout when forced
+func (c *OnvifClient) StopMetadata(force bool) {
+	if c.metadata == nil {
+		return
+	}
+
+	close(c.metadata.stop)
+	client.WaitStopped(c.metadata.stopped, force)
+	c.metadata = nil
+}
+
+func (l *metadataListener) run() {
+	defer close(l.stopped)
+
+	for {
+		err := l.readMetadata()
+		if err == nil {
+			return
+		}
+		l.client.lc.Errorf("ONVIF metadata stream for device '%s' failed: %s", l.device.Name, err.Error())
+
+		select {
+		case <-l.stop:
+			return
+		case <-time.After(pullRetryWait):
+		}
+	}
+}
+
+func (l *metadataListener) readMetadata() error {
+	uri, err := l.client.getMetadataStreamURI()
+	if err != nil {
+		return err
+	}
+
+	rtspClient, err := rtsp.NewClient(uri, l.client.user, l.client.password)
+	if err != nil {
+		return err
+	}
+
+	return rtspClient.ReadMetadata(l.handleDocument, l.stop)
+}
+
+func (l *metadataListener) handleDocument(document []byte) {
+	frames, err := parseMetadataFrames(document)
+	if err != nil {
+		l.client.lc.Warnf("Invalid ONVIF metadata from device '%s': %s", l.device.Name, err.Error())
+		return
+	}
+	if len(frames) == 0 {
+		return
+	}
+
+	var cvs []*sdkModel.CommandValue
+	for _, mr := range l.resources {
+		cv, ok, err := metadataCommandValue(mr, frames)
+		if err != nil {
+			l.client.lc.Warnf("Unable to create reading for resource '%s': %s", mr.resource.Name, err.Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+		cv.Origin = time.Now().UnixNano() / int64(time.Millisecond)
+		cvs = append(cvs, cv)
+	}
+
+	if len(cvs) > 0 {
+		// the stream ends on stop, so values of a stopped listener are dropped
+		client.SendAsyncValues(l.asyncCh, l.stop, &sdkModel.AsyncValues{DeviceName: l.device.Name, CommandValues: cvs})
+	}
+}
+
+// getMetadataStreamURI returns the RTSP URI of the first media profile with a metadata configuration
+func (c *OnvifClient) getMetadataStreamURI() (string, error) {
+	profilesResp, err := c.onvifDevice.Media.GetProfiles()
+	if err != nil {
+		return "", fmt.Errorf("GetProfiles failed: %v", err.Error())
+	}
+
+	for _, profile := range profilesResp.Profiles {
+		if profile.MetadataConfiguration == nil {
+			continue
+		}
+
+		uriResp, err := c.onvifDevice.Media.GetStreamURI(string(profile.Token), "RTP-Unicast", "RTSP")
+		if err != nil {
+			return "", fmt.Errorf("GetStreamURI failed: %v", err.Error())
+		}
+		return string(uriResp.MediaUri.Uri), nil
+	}
+
+	return "", errors.New("no onvif profile with a metadata configuration found")
+}
+
+// parseMetadataFrames returns the video analytics frames of a tt:MetadataStream document
+func parseMetadataFrames(document []byte) ([]metadataFrame, error) {
+	var stream metadataStream
+	err := xml.Unmarshal(document, &stream)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make([]metadataFrame, 0, len(stream.Frames))
+	for _, f := range stream.Frames {
+		frame := metadataFrame{UtcTime: f.UtcTime, Objects: []metadataObject{}}
+		for _, o := range f.Objects {
+			object := metadataObject{
+				ObjectID:        o.ObjectID,
+				BoundingBox:     o.Appearance.Shape.BoundingBox,
+				CenterOfGravity: o.Appearance.Shape.CenterOfGravity,
+				Speed:           o.Behaviour.Speed,
+			}
+
+			// the class is the most likely candidate
+			for _, c := range o.Appearance.Class.Candidates {
+				if object.Class == "" || c.Likelihood > object.Likelihood {
+					object.Class, object.Likelihood = strings.TrimSpace(c.Type), c.Likelihood
+				}
+			}
+			for _, t := range o.Appearance.Class.Types {
+				if object.Class == "" || t.Likelihood > object.Likelihood {
+					object.Class, object.Likelihood = strings.TrimSpace(t.Type), t.Likelihood
+				}
+			}
+
+			frame.Objects = append(frame.Objects, object)
+		}
+		frames = append(frames, frame)
+	}
+
+	return frames, nil
+}
+
+// metadataCommandValue returns the frames, limited to the objects of the resource's class, as a
+// reading of the resource.  Resources with a class have no reading, indicated by false, when no
+// object of the class is in the frames.
+func metadataCommandValue(mr metadataResource, frames []metadataFrame) (*sdkModel.CommandValue, bool, error) {
+	if mr.class != "" {
+		matched := false
+		filtered := make([]metadataFrame, len(frames))
+		for i, frame := range frames {
+			filtered[i] = metadataFrame{UtcTime: frame.UtcTime, Objects: []metadataObject{}}
+			for _, object := range frame.Objects {
+				if strings.EqualFold(object.Class, mr.class) {
+					filtered[i].Objects = append(filtered[i].Objects, object)
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			return nil, false, nil
+		}
+		frames = filtered
+	}
+
+	if mr.resource.Properties.ValueType == common.ValueTypeObject {
+		cv, err := sdkModel.NewCommandValue(mr.resource.Name, common.ValueTypeObject, frames)
+		return cv, true, err
+	}
+
+	framesJSON, err := json.Marshal(frames)
+	if err != nil {
+		return nil, false, err
+	}
+	cv, err := sdkModel.NewCommandValue(mr.resource.Name, common.ValueTypeString, string(framesJSON))
+	return cv, true, err
+}
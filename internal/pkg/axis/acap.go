@@ -0,0 +1,241 @@
+package axis
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+const (
+	vapixACAPListFmtURL    = "%s/axis-cgi/applications/list.cgi"
+	vapixACAPControlFmtURL = "%s/axis-cgi/applications/control.cgi?%s"
+	vapixACAPUploadFmtURL  = "%s/axis-cgi/applications/upload.cgi"
+
+	// values of the axis_acap attribute
+	acapList    = "list"
+	acapStatus  = "status"
+	acapParams  = "params"
+	acapStart   = "start"
+	acapStop    = "stop"
+	acapRestart = "restart"
+	acapUpload  = "upload"
+)
+
+// acapApplication is an installed ACAP application as reported by list.cgi
+type acapApplication struct {
+	Name          string `xml:"Name,attr"`
+	NiceName      string `xml:"NiceName,attr"`
+	Vendor        string `xml:"Vendor,attr"`
+	Version       string `xml:"Version,attr"`
+	ApplicationID string `xml:"ApplicationID,attr"`
+	License       string `xml:"License,attr"`
+	Status        string `xml:"Status,attr"`
+}
+
+type acapListReply struct {
+	Result       string            `xml:"result,attr"`
+	Applications []acapApplication `xml:"application"`
+	Error        struct {
+		Code    string `xml:"code,attr"`
+		Message string `xml:"message,attr"`
+	} `xml:"error"`
+}
+
+// readACAP reads the command given by the resource's axis_acap attribute: list, the installed
+// applications, or status and params, the status and the parameters of the application given by
+// the axis_acap_package attribute
+func (c *VapixClient) readACAP(req sdkModels.CommandRequest) (*sdkModels.CommandValue, error) {
+	command, _ := req.Attributes["axis_acap"].(string)
+	pkg, _ := req.Attributes["axis_acap_package"].(string)
+
+	var value interface{}
+	switch command {
+	case acapList:
+		apps, err := c.listApplications()
+		if err != nil {
+			return nil, err
+		}
+		value = apps
+	case acapStatus:
+		if pkg == "" {
+			return nil, fmt.Errorf("vapix: acap status requires an axis_acap_package attribute")
+		}
+		apps, err := c.listApplications()
+		if err != nil {
+			return nil, err
+		}
+		app, ok := findApplication(apps, pkg)
+		if !ok {
+			return nil, fmt.Errorf("vapix: acap %s is not installed", pkg)
+		}
+		return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeString, app.Status)
+	case acapParams:
+		if pkg == "" {
+			return nil, fmt.Errorf("vapix: acap params requires an axis_acap_package attribute")
+		}
+		// applications keep their parameters in a param.cgi group named after the package
+		params, err := c.listParams(pkg)
+		if err != nil {
+			return nil, err
+		}
+		value = params
+	default:
+		return nil, fmt.Errorf("vapix: unsupported axis_acap read %s", command)
+	}
+
+	if req.Type == common.ValueTypeObject {
+		return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeObject, value)
+	}
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeString, string(valueJSON))
+}
+
+// writeACAP starts, stops or restarts the application whose package name is written, or uploads
+// the .eap file, named by the value written, from the directory given by the resource's
+// axis_acap_directory attribute
+func (c *VapixClient) writeACAP(req sdkModels.CommandRequest, param *sdkModels.CommandValue) error {
+	command, _ := req.Attributes["axis_acap"].(string)
+
+	value, err := param.StringValue()
+	if err != nil || strings.TrimSpace(value) == "" {
+		return fmt.Errorf("vapix: acap %s requires a String value", command)
+	}
+
+	switch command {
+	case acapStart, acapStop, acapRestart:
+		return c.controlApplication(command, value)
+	case acapUpload:
+		directory, _ := req.Attributes["axis_acap_directory"].(string)
+		path, err := acapPackagePath(directory, value)
+		if err != nil {
+			return err
+		}
+		return c.uploadApplication(path)
+	}
+	return fmt.Errorf("vapix: unsupported axis_acap write %s", command)
+}
+
+func (c *VapixClient) listApplications() ([]acapApplication, error) {
+	body, err := getBody(c.client, fmt.Sprintf(vapixACAPListFmtURL, c.baseURL))
+	if err != nil {
+		return nil, fmt.Errorf("vapix: listing applications: %v", err.Error())
+	}
+	return parseApplications(body)
+}
+
+// parseApplications parses the reply of list.cgi
+func parseApplications(body []byte) ([]acapApplication, error) {
+	var reply acapListReply
+	err := xml.Unmarshal(body, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("vapix: parsing applications: %v", err.Error())
+	}
+	if reply.Result != "ok" {
+		return nil, fmt.Errorf("vapix: listing applications: error %s %s", reply.Error.Code, reply.Error.Message)
+	}
+	if reply.Applications == nil {
+		return []acapApplication{}, nil
+	}
+	return reply.Applications, nil
+}
+
+func findApplication(apps []acapApplication, pkg string) (acapApplication, bool) {
+	for _, app := range apps {
+		if app.Name == pkg {
+			return app, true
+		}
+	}
+	return acapApplication{}, false
+}
+
+func (c *VapixClient) controlApplication(action string, pkg string) error {
+	query := url.Values{"action": {action}, "package": {pkg}}
+
+	body, err := getBody(c.client, fmt.Sprintf(vapixACAPControlFmtURL, c.baseURL, query.Encode()))
+	if err != nil {
+		return fmt.Errorf("vapix: %s acap %s: %v", action, pkg, err.Error())
+	}
+	return acapReplyError(action, pkg, body)
+}
+
+// acapReplyError returns the error of a control.cgi or upload.cgi reply, which is "OK" on success
+// and "Error: <code>" otherwise
+func acapReplyError(action string, pkg string, body []byte) error {
+	if reply := strings.TrimSpace(string(body)); reply != "OK" {
+		return fmt.Errorf("vapix: %s acap %s: %s", action, pkg, reply)
+	}
+	return nil
+}
+
+// acapPackagePath returns the path of an .eap file in the directory, refusing names which
+// would leave it
+func acapPackagePath(directory string, name string) (string, error) {
+	if directory == "" {
+		return "", fmt.Errorf("vapix: acap upload requires an axis_acap_directory attribute")
+	}
+	if name != filepath.Base(name) || !strings.HasSuffix(name, ".eap") {
+		return "", fmt.Errorf("vapix: invalid acap package file %s", name)
+	}
+	return filepath.Join(directory, name), nil
+}
+
+// uploadApplication installs the .eap file, replacing an installed version of the application
+func (c *VapixClient) uploadApplication(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("vapix: acap upload: %v", err.Error())
+	}
+	defer file.Close()
+
+	// the body is buffered so that it can be resent after a digest authentication challenge
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("packfil", filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("vapix: acap upload: %v", err.Error())
+	}
+	_, err = io.Copy(part, file)
+	if err != nil {
+		return fmt.Errorf("vapix: acap upload: reading %s: %v", path, err.Error())
+	}
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("vapix: acap upload: %v", err.Error())
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(vapixACAPUploadFmtURL, c.baseURL), bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return fmt.Errorf("vapix: new request POST Error: %v", err.Error())
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vapix: acap upload POST Error: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vapix: acap upload status Error: %v", resp.StatusCode)
+	}
+	reply, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("vapix: acap upload read Error: %v", err.Error())
+	}
+	return acapReplyError(acapUpload, filepath.Base(path), reply)
+}
@@ -0,0 +1,119 @@
+package axis
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+func TestParseApplications(t *testing.T) {
+	body := `<reply result="ok">
+ <application Name="vmd" NiceName="AXIS Video Motion Detection" Vendor="Axis Communications" Version="4.5-5" ApplicationID="143440" License="None" Status="Running" ConfigurationPage="local/vmd/config.html" VendorHomePage="http://www.axis.com" />
+ <application Name="objectanalytics" NiceName="AXIS Object Analytics" Vendor="Axis Communications" Version="1.0.1" ApplicationID="415508" License="None" Status="Stopped" />
+</reply>`
+
+	apps, err := parseApplications([]byte(body))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []acapApplication{
+		{Name: "vmd", NiceName: "AXIS Video Motion Detection", Vendor: "Axis Communications", Version: "4.5-5", ApplicationID: "143440", License: "None", Status: "Running"},
+		{Name: "objectanalytics", NiceName: "AXIS Object Analytics", Vendor: "Axis Communications", Version: "1.0.1", ApplicationID: "415508", License: "None", Status: "Stopped"},
+	}
+	if !reflect.DeepEqual(apps, expected) {
+		t.Errorf("Expected %+v, Received %+v", expected, apps)
+	}
+
+	_, err = parseApplications([]byte(`<reply result="error"><error code="4" message="Invalid request" /></reply>`))
+	if err == nil {
+		t.Error("Expected error for an error reply")
+	}
+}
+
+func TestACAPPackagePath(t *testing.T) {
+	tests := []struct {
+		directory string
+		name      string
+		expected  string
+		err       bool
+	}{
+		{"/opt/acap", "vmd_4_5_5_armv7hf.eap", "/opt/acap/vmd_4_5_5_armv7hf.eap", false},
+		{"", "vmd.eap", "", true},
+		{"/opt/acap", "../secrets/vmd.eap", "", true},
+		{"/opt/acap", "/etc/passwd", "", true},
+		{"/opt/acap", "vmd.tar", "", true},
+	}
+
+	for _, test := range tests {
+		path, err := acapPackagePath(test.directory, test.name)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s %s: Expected error", test.directory, test.name)
+			}
+			continue
+		}
+		if err != nil || path != test.expected {
+			t.Errorf("%s %s: Expected %s, Received %s (%v)", test.directory, test.name, test.expected, path, err)
+		}
+	}
+}
+
+func TestUploadApplication(t *testing.T) {
+	directory, err := ioutil.TempDir("", "acap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	err = ioutil.WriteFile(filepath.Join(directory, "app.eap"), []byte("package contents"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// challenge the first attempt, so that the upload is resent with digest authentication
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Set("WWW-Authenticate", `Digest realm="AXIS", nonce="abc", qop="auth"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		file, _, err := r.FormFile("packfil")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		contents, _ := ioutil.ReadAll(file)
+		uploaded = string(contents)
+		_, _ = w.Write([]byte("OK\n"))
+	}))
+	defer server.Close()
+
+	c := NewClient(nil, logger.NewMockClient()).(*VapixClient)
+	c.CameraInit(models.Device{Name: "Camera001"}, models.DeviceProfile{}, strings.TrimPrefix(server.URL, "http://"), "root", "pass")
+	defer c.CameraRelease(false)
+
+	req := sdkModels.CommandRequest{
+		DeviceResourceName: "ACAPUpload",
+		Attributes:         map[string]interface{}{"axis_acap": "upload", "axis_acap_directory": directory},
+		Type:               common.ValueTypeString,
+	}
+	param, _ := sdkModels.NewCommandValue("ACAPUpload", common.ValueTypeString, "app.eap")
+
+	err = c.HandleWriteCommand(req, param)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if uploaded != "package contents" {
+		t.Errorf("Expected the package contents, Received %q", uploaded)
+	}
+}
@@ -0,0 +1,124 @@
+package axis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+const (
+	vapixAOAControlFmtURL = "%s/local/objectanalytics/control.cgi"
+
+	// aoaTopicFmt is the event topic of an AXIS Object Analytics scenario of a device
+	aoaTopicFmt = "tnsaxis:CameraApplicationPlatform/ObjectAnalytics/Device%dScenario%d"
+
+	defaultScenarioItem = "active"
+)
+
+// scenarioResource is a device resource whose readings come from the events of an AXIS Object
+// Analytics scenario.  It is configured by the resource attributes axis_aoa_scenario, the name
+// of the scenario as shown in the application, e.g. "Entrance", and axis_aoa_item, the data item
+// of its events: "active" by default, which is true while the scenario is triggered, e.g. by an
+// object crossing a line, or a count such as "total" or "totalHuman" of counting scenarios.
+type scenarioResource struct {
+	resource models.DeviceResource
+	scenario string
+	item     string
+}
+
+// aoaScenario is a scenario of the AXIS Object Analytics configuration
+type aoaScenario struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Devices []struct {
+		ID int `json:"id"`
+	} `json:"devices"`
+}
+
+// aoaConfiguration is the data of the getConfiguration reply
+type aoaConfiguration struct {
+	Scenarios []aoaScenario `json:"scenarios"`
+}
+
+func scenarioResourcesFromProfile(profile models.DeviceProfile) []scenarioResource {
+	var resources []scenarioResource
+	for _, dr := range profile.DeviceResources {
+		scenario, ok := dr.Attributes["axis_aoa_scenario"].(string)
+		if !ok {
+			continue
+		}
+		item, _ := dr.Attributes["axis_aoa_item"].(string)
+		if item == "" {
+			item = defaultScenarioItem
+		}
+
+		resources = append(resources, scenarioResource{resource: dr, scenario: scenario, item: item})
+	}
+	return resources
+}
+
+// getScenarios reads the scenarios of the AXIS Object Analytics configuration
+func (c *VapixClient) getScenarios() ([]aoaScenario, error) {
+	var configuration aoaConfiguration
+	err := c.postJSONAPI(fmt.Sprintf(vapixAOAControlFmtURL, c.baseURL), "getConfiguration", &configuration)
+	if err != nil {
+		return nil, fmt.Errorf("vapix: object analytics configuration: %v", err.Error())
+	}
+	return configuration.Scenarios, nil
+}
+
+// scenarioEventResources maps the scenario resources to the event topics of the scenarios of
+// their names.  The names of scenarios which aren't configured are returned.
+func scenarioEventResources(resources []scenarioResource, scenarios []aoaScenario) ([]eventResource, []string) {
+	var events []eventResource
+	var missing []string
+	for _, sr := range resources {
+		found := false
+		for _, scenario := range scenarios {
+			if !strings.EqualFold(scenario.Name, sr.scenario) {
+				continue
+			}
+			device := 1
+			if len(scenario.Devices) > 0 {
+				device = scenario.Devices[0].ID
+			}
+
+			events = append(events, eventResource{resource: sr.resource, topic: fmt.Sprintf(aoaTopicFmt, device, scenario.ID), item: sr.item})
+			found = true
+			break
+		}
+		if !found {
+			missing = append(missing, sr.scenario)
+		}
+	}
+	return events, missing
+}
+
+// streamEvents returns the event resources to subscribe to: the configured ones, and those of the
+// scenario resources, whose topics are looked up by their names on each connection so that
+// scenarios renamed or recreated in the application are picked up
+func (c *VapixClient) streamEvents() ([]eventResource, error) {
+	if len(c.scenarios) == 0 {
+		return c.events, nil
+	}
+
+	events := append([]eventResource{}, c.events...)
+
+	scenarios, err := c.getScenarios()
+	if err != nil {
+		c.lc.Warnf("Unable to read AXIS Object Analytics scenarios: %s", err.Error())
+	} else {
+		resolved, missing := scenarioEventResources(c.scenarios, scenarios)
+		if len(missing) > 0 {
+			c.lc.Warnf("AXIS Object Analytics scenarios not configured: %s", strings.Join(missing, ", "))
+		}
+		events = append(events, resolved...)
+	}
+
+	if len(events) == 0 {
+		return nil, fmt.Errorf("vapix: no events to subscribe to")
+	}
+	return events, nil
+}
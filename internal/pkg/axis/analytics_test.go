@@ -0,0 +1,61 @@
+package axis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+func TestScenarioEventResources(t *testing.T) {
+	reply := `{"apiVersion":"1.0","context":"edgex","method":"getConfiguration","data":{
+		"devices":[{"id":1,"type":"camera"}],
+		"scenarios":[
+			{"id":1,"name":"Entrance","type":"crosslinecounting","devices":[{"id":1}]},
+			{"id":3,"name":"Loading Bay","type":"motion","devices":[{"id":2}]}
+		]}}`
+
+	var configuration aoaConfiguration
+	err := decodeJSONAPIReply("getConfiguration", []byte(reply), &configuration)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	scenarios := configuration.Scenarios
+
+	profile := models.DeviceProfile{DeviceResources: []models.DeviceResource{
+		{Name: "EntranceCrossed", Attributes: map[string]interface{}{"axis_aoa_scenario": "Entrance"}},
+		{Name: "EntrancePeople", Attributes: map[string]interface{}{"axis_aoa_scenario": "entrance", "axis_aoa_item": "totalHuman"}},
+		{Name: "LoadingBayVehicle", Attributes: map[string]interface{}{"axis_aoa_scenario": "Loading Bay"}},
+		{Name: "Parking", Attributes: map[string]interface{}{"axis_aoa_scenario": "Parking"}},
+		{Name: "Motion", Attributes: map[string]interface{}{"axis_event_topic": "tns1:VideoSource/MotionAlarm"}},
+	}}
+	resources := scenarioResourcesFromProfile(profile)
+
+	events, missing := scenarioEventResources(resources, scenarios)
+
+	expected := []struct {
+		name  string
+		topic string
+		item  string
+	}{
+		{"EntranceCrossed", "tnsaxis:CameraApplicationPlatform/ObjectAnalytics/Device1Scenario1", "active"},
+		{"EntrancePeople", "tnsaxis:CameraApplicationPlatform/ObjectAnalytics/Device1Scenario1", "totalHuman"},
+		{"LoadingBayVehicle", "tnsaxis:CameraApplicationPlatform/ObjectAnalytics/Device2Scenario3", "active"},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d event resources, Received %d", len(expected), len(events))
+	}
+	for i, e := range expected {
+		if events[i].resource.Name != e.name || events[i].topic != e.topic || events[i].item != e.item {
+			t.Errorf("Expected %+v, Received %s %s %s", e, events[i].resource.Name, events[i].topic, events[i].item)
+		}
+	}
+	if !reflect.DeepEqual(missing, []string{"Parking"}) {
+		t.Errorf("Expected missing [Parking], Received %v", missing)
+	}
+
+	err = decodeJSONAPIReply("getConfiguration", []byte(`{"apiVersion":"1.0","error":{"code":2001,"message":"Method not supported"}}`), &configuration)
+	if err == nil {
+		t.Error("Expected error for an error reply")
+	}
+}
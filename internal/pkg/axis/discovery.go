@@ -0,0 +1,221 @@
+package axis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+const (
+	vapixBasicDeviceInfoFmtURL = "%s/axis-cgi/basicdeviceinfo.cgi"
+	vapixAPIDiscoveryFmtURL    = "%s/axis-cgi/apidiscovery.cgi"
+
+	discoveryTimeout = 10 * time.Second
+)
+
+// eventStreamAPIs are the apidiscovery IDs under which firmware lists the WebSocket event stream
+var eventStreamAPIs = []string{"event-streaming-over-websocket", "ws-data-stream"}
+
+// inventory is the camera's product information and supported APIs, as reported by
+// basicdeviceinfo.cgi and apidiscovery.cgi
+type inventory struct {
+	ProductNumber string
+	ProductName   string
+	Brand         string
+	SerialNumber  string
+	HardwareID    string
+	Firmware      string
+	SupportedAPIs []vapixAPI
+}
+
+type vapixAPI struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	Name    string `json:"name"`
+}
+
+type jsonAPIReply struct {
+	Data  json.RawMessage `json:"data"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// supports reports whether the inventory lists any of the APIs
+func (inv inventory) supports(ids ...string) bool {
+	for _, api := range inv.SupportedAPIs {
+		for _, id := range ids {
+			if api.ID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getInventory reads the camera's product information and supported APIs
+func (c *VapixClient) getInventory() (inventory, error) {
+	var inv inventory
+
+	var info struct {
+		PropertyList struct {
+			ProdNbr      string
+			ProdFullName string
+			Brand        string
+			SerialNumber string
+			HardwareID   string
+			Version      string
+		} `json:"propertyList"`
+	}
+	err := c.postJSONAPI(fmt.Sprintf(vapixBasicDeviceInfoFmtURL, c.baseURL), "getAllProperties", &info)
+	if err != nil {
+		return inv, fmt.Errorf("vapix: basic device info: %v", err.Error())
+	}
+	inv.ProductNumber = info.PropertyList.ProdNbr
+	inv.ProductName = info.PropertyList.ProdFullName
+	inv.Brand = info.PropertyList.Brand
+	inv.SerialNumber = info.PropertyList.SerialNumber
+	inv.HardwareID = info.PropertyList.HardwareID
+	inv.Firmware = info.PropertyList.Version
+
+	var apis struct {
+		APIList []vapixAPI `json:"apiList"`
+	}
+	err = c.postJSONAPI(fmt.Sprintf(vapixAPIDiscoveryFmtURL, c.baseURL), "getApiList", &apis)
+	if err != nil {
+		return inv, fmt.Errorf("vapix: api discovery: %v", err.Error())
+	}
+	inv.SupportedAPIs = apis.APIList
+	if inv.SupportedAPIs == nil {
+		inv.SupportedAPIs = []vapixAPI{}
+	}
+
+	return inv, nil
+}
+
+// readInventory reads the inventory, as an object or, for String resources, as JSON
+func (c *VapixClient) readInventory(req sdkModels.CommandRequest) (*sdkModels.CommandValue, error) {
+	inv, err := c.getInventory()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Type == common.ValueTypeObject {
+		return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeObject, inv)
+	}
+	invJSON, err := json.Marshal(inv)
+	if err != nil {
+		return nil, err
+	}
+	return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeString, string(invJSON))
+}
+
+// enableSupportedListeners adapts the listeners to the APIs of the camera.  Cameras without the
+// WebSocket event stream get no event listener, and the axis_event_topic resources no readings.
+// Cameras with it read the alarm codes which have an event equivalent from the event stream
+// rather than the legacy MJPEG stream, except for resources limited to a video channel by
+// axis_camera.
+func (c *VapixClient) enableSupportedListeners(inv inventory) {
+	if !inv.supports(eventStreamAPIs...) {
+		if len(c.events) > 0 || len(c.scenarios) > 0 {
+			c.lc.Warnf("Axis %s firmware %s doesn't support the WebSocket event stream, event resources are disabled", inv.ProductNumber, inv.Firmware)
+		}
+		c.events, c.scenarios = nil, nil
+		return
+	}
+
+	for alarmCode, resources := range c.alarms {
+		topic, item, source, ok := legacyAlarmEvent(alarmCode)
+		if !ok {
+			continue
+		}
+
+		// the events don't identify the video channel of an axis_camera attribute, so those
+		// resources stay on the MJPEG listener of their channel
+		var remaining []models.DeviceResource
+		for _, dr := range resources {
+			if _, ok := dr.Attributes["axis_camera"]; ok {
+				remaining = append(remaining, dr)
+				continue
+			}
+			c.events = append(c.events, eventResource{resource: dr, topic: topic, item: item, source: source})
+		}
+		if len(remaining) > 0 {
+			c.alarms[alarmCode] = remaining
+		} else {
+			delete(c.alarms, alarmCode)
+		}
+	}
+}
+
+// legacyAlarmEvent returns the event of an MJPEG alarm code: motion in window n for "Mn" and
+// tampering of video channel n for "Tn"
+func legacyAlarmEvent(alarmCode string) (string, string, string, bool) {
+	if len(alarmCode) != 2 || alarmCode[1] < '0' || alarmCode[1] > '9' {
+		return "", "", "", false
+	}
+	source := alarmCode[1:]
+
+	switch alarmCode[0] {
+	case 'M':
+		return "tns1:VideoAnalytics/tnsaxis:MotionDetection", "motion", source, true
+	case 'T':
+		return "tns1:VideoSource/tnsaxis:Tampering", "tampering", source, true
+	}
+	return "", "", "", false
+}
+
+// postJSONAPI calls a method of a JSON VAPIX API and decodes the data of its reply
+func (c *VapixClient) postJSONAPI(apiURL string, method string, data interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	body := []byte(fmt.Sprintf(`{"apiVersion":"1.0","context":"edgex","method":"%s"}`, method))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request POST Error: %v", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST Error: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status Error: %v", resp.StatusCode)
+	}
+	replyJSON, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read Error: %v", err.Error())
+	}
+
+	return decodeJSONAPIReply(method, replyJSON, data)
+}
+
+// decodeJSONAPIReply decodes the data of a JSON VAPIX API reply, or returns its error
+func decodeJSONAPIReply(method string, replyJSON []byte, data interface{}) error {
+	var reply jsonAPIReply
+	err := json.Unmarshal(replyJSON, &reply)
+	if err != nil {
+		return fmt.Errorf("error unmarshaling reply: %v", err.Error())
+	}
+	if reply.Error != nil {
+		return fmt.Errorf("%s failed: %s", method, strings.TrimSpace(reply.Error.Message))
+	}
+	if len(reply.Data) == 0 {
+		return fmt.Errorf("%s replied without data", method)
+	}
+	return json.Unmarshal(reply.Data, data)
+}
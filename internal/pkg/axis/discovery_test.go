@@ -0,0 +1,113 @@
+package axis
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+func TestGetInventory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &req)
+
+		switch {
+		case r.URL.Path == "/axis-cgi/basicdeviceinfo.cgi" && req.Method == "getAllProperties":
+			_, _ = w.Write([]byte(`{"apiVersion":"1.1","data":{"propertyList":{"Architecture":"armv7hf","Brand":"AXIS",` +
+				`"HardwareID":"72A","ProdFullName":"AXIS M3104-L Network Camera","ProdNbr":"M3104-L","SerialNumber":"ACCC8E000001","Version":"9.80.3.1"}}}`))
+		case r.URL.Path == "/axis-cgi/apidiscovery.cgi" && req.Method == "getApiList":
+			_, _ = w.Write([]byte(`{"apiVersion":"1.0","data":{"apiList":[` +
+				`{"id":"basic-device-info","version":"1.1","name":"Basic Device Information","docLink":""},` +
+				`{"id":"param-cgi","version":"1.0","name":"Legacy Parameter Handling","docLink":""}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := &VapixClient{lc: logger.NewMockClient()}
+	c.client = http.DefaultClient
+	c.baseURL = server.URL
+
+	inv, err := c.getInventory()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := inventory{
+		ProductNumber: "M3104-L",
+		ProductName:   "AXIS M3104-L Network Camera",
+		Brand:         "AXIS",
+		SerialNumber:  "ACCC8E000001",
+		HardwareID:    "72A",
+		Firmware:      "9.80.3.1",
+		SupportedAPIs: []vapixAPI{
+			{ID: "basic-device-info", Version: "1.1", Name: "Basic Device Information"},
+			{ID: "param-cgi", Version: "1.0", Name: "Legacy Parameter Handling"},
+		},
+	}
+	if !reflect.DeepEqual(inv, expected) {
+		t.Errorf("Expected %+v, Received %+v", expected, inv)
+	}
+}
+
+func TestEnableSupportedListeners(t *testing.T) {
+	motion := models.DeviceResource{Name: "MotionDetected", Attributes: map[string]interface{}{"alarm_code": "M0"}}
+	input := models.DeviceResource{Name: "Input", Attributes: map[string]interface{}{"alarm_code": "I1"}}
+	tampering := models.DeviceResource{Name: "Tampering", Attributes: map[string]interface{}{"alarm_code": "T0"}}
+	tampering2 := models.DeviceResource{Name: "Tampering2", Attributes: map[string]interface{}{"alarm_code": "T0", "axis_camera": "2"}}
+	event := eventResource{resource: models.DeviceResource{Name: "MotionAlarm"}, topic: "tns1:VideoSource/MotionAlarm", item: "State"}
+
+	newClient := func() *VapixClient {
+		return &VapixClient{
+			lc:     logger.NewMockClient(),
+			alarms: map[string][]models.DeviceResource{"M0": {motion}, "I1": {input}, "T0": {tampering, tampering2}},
+			events: []eventResource{event},
+		}
+	}
+
+	// without the event stream, the event resources are disabled and the alarm codes kept
+	c := newClient()
+	c.enableSupportedListeners(inventory{SupportedAPIs: []vapixAPI{{ID: "param-cgi"}}})
+	if len(c.events) != 0 || len(c.alarms) != 3 {
+		t.Errorf("Expected no events and 3 alarm codes, Received %d events and %d alarm codes", len(c.events), len(c.alarms))
+	}
+
+	// with it, motion and tampering are read from the event stream, and the unknown code and
+	// the resource of a video channel from MJPEG
+	c = newClient()
+	c.enableSupportedListeners(inventory{SupportedAPIs: []vapixAPI{{ID: "event-streaming-over-websocket"}}})
+	expected := []eventResource{
+		event,
+		{resource: motion, topic: "tns1:VideoAnalytics/tnsaxis:MotionDetection", item: "motion", source: "0"},
+		{resource: tampering, topic: "tns1:VideoSource/tnsaxis:Tampering", item: "tampering", source: "0"},
+	}
+	// the alarm codes are mapped in no particular order
+	sort.Slice(c.events, func(i, j int) bool { return c.events[i].resource.Name < c.events[j].resource.Name })
+	sort.Slice(expected, func(i, j int) bool { return expected[i].resource.Name < expected[j].resource.Name })
+	if !reflect.DeepEqual(c.events, expected) {
+		t.Errorf("Expected events %+v, Received %+v", expected, c.events)
+	}
+	expectedAlarms := map[string][]models.DeviceResource{"I1": {input}, "T0": {tampering2}}
+	if !reflect.DeepEqual(c.alarms, expectedAlarms) {
+		t.Errorf("Expected alarm codes %v, Received %v", expectedAlarms, c.alarms)
+	}
+}
+
+func TestDecodeJSONAPIReply(t *testing.T) {
+	var data map[string]interface{}
+	err := decodeJSONAPIReply("getApiList", []byte(`{"apiVersion":"1.0","error":{"code":4002,"message":" Unsupported method "}}`), &data)
+	if err == nil || !strings.Contains(err.Error(), "getApiList failed: Unsupported method") {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
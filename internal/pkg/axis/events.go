@@ -0,0 +1,226 @@
+package axis
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/gorilla/websocket"
+
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/eventtopic"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/reading"
+)
+
+const (
+	vapixWSSessionFmtURL  = "%s/axis-cgi/wssession.cgi"
+	vapixDataStreamFmtURL = "%s/vapix/ws-data-stream?sources=events"
+
+	eventsAPIVersion = "1.0"
+	eventsConfigure  = "events:configure"
+	eventsNotify     = "events:notify"
+
+	wsHandshakeTimeout = 10 * time.Second
+)
+
+// eventResource is a device resource whose readings come from the Axis event stream.  It is
+// configured by the resource attributes axis_event_topic, e.g.
+// "tns1:VideoSource/MotionAlarm", and axis_event_item, the name of the data item holding the
+// value, e.g. "State".  The optional axis_event_source attribute selects notifications by the
+// value of a source or key item, e.g. the port of an I/O event.
+type eventResource struct {
+	resource models.DeviceResource
+	topic    string
+	item     string
+	source   string
+}
+
+// eventsRequest is a request of the event streaming API
+type eventsRequest struct {
+	APIVersion string       `json:"apiVersion"`
+	Method     string       `json:"method"`
+	Params     eventsParams `json:"params"`
+}
+
+type eventsParams struct {
+	EventFilterList []eventFilter `json:"eventFilterList"`
+}
+
+type eventFilter struct {
+	TopicFilter string `json:"topicFilter"`
+}
+
+// eventsMessage is a message of the event streaming API: the reply to events:configure, an
+// error, or an events:notify notification
+type eventsMessage struct {
+	Method string `json:"method"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Params struct {
+		Notification struct {
+			Topic   string `json:"topic"`
+			Message struct {
+				Source map[string]interface{} `json:"source"`
+				Key    map[string]interface{} `json:"key"`
+				Data   map[string]interface{} `json:"data"`
+			} `json:"message"`
+		} `json:"notification"`
+	} `json:"params"`
+}
+
+func eventResourcesFromProfile(profile models.DeviceProfile) []eventResource {
+	var resources []eventResource
+	for _, dr := range profile.DeviceResources {
+		topic, ok := dr.Attributes["axis_event_topic"].(string)
+		if !ok {
+			continue
+		}
+		item, _ := dr.Attributes["axis_event_item"].(string)
+		source, _ := dr.Attributes["axis_event_source"].(string)
+
+		resources = append(resources, eventResource{resource: dr, topic: topic, item: item, source: source})
+	}
+	return resources
+}
+
+// listenForEvents subscribes to the topics of the event resources over the WebSocket event stream
+// and sends the values of their notifications as async readings until the client is released
+func (c *VapixClient) listenForEvents(edgexDevice models.Device, username string, password string) error {
+	events, err := c.streamEvents()
+	if err != nil {
+		return fmt.Errorf("listenForEvents: %v", err.Error())
+	}
+
+	conn, err := c.dialDataStream(username, password)
+	if err != nil {
+		return fmt.Errorf("listenForEvents: %v", err.Error())
+	}
+
+	// closing the connection ends the blocking read below when the client is released
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		select {
+		case <-c.stop:
+		case <-done:
+		}
+		conn.Close()
+	}()
+
+	err = conn.WriteJSON(eventsConfigureRequest(events))
+	if err != nil {
+		return c.eventsError(err)
+	}
+
+	for {
+		var msg eventsMessage
+		err = conn.ReadJSON(&msg)
+		if err != nil {
+			return c.eventsError(err)
+		}
+
+		if msg.Error != nil {
+			return fmt.Errorf("listenForEvents: %s failed: %s", eventsConfigure, msg.Error.Message)
+		}
+		if msg.Method != eventsNotify {
+			continue
+		}
+
+		cvs := c.eventCommandValues(events, msg)
+		if len(cvs) > 0 && !c.sendEvent(edgexDevice, cvs) {
+			return errCancelled
+		}
+	}
+}
+
+// dialDataStream opens the WebSocket event stream.  Cameras requiring digest authentication accept
+// a session token from wssession.cgi, older firmware basic authentication of the handshake.
+func (c *VapixClient) dialDataStream(username string, password string) (*websocket.Conn, error) {
+	// ws:// or wss:// by the scheme of the camera's base URL
+	streamURL := "ws" + strings.TrimPrefix(fmt.Sprintf(vapixDataStreamFmtURL, c.baseURL), "http")
+	header := http.Header{}
+
+	token, err := getBody(c.client, fmt.Sprintf(vapixWSSessionFmtURL, c.baseURL))
+	if err == nil && len(strings.TrimSpace(string(token))) > 0 {
+		streamURL += "&" + url.Values{"wssession": {strings.TrimSpace(string(token))}}.Encode()
+	} else if username != "" {
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+	}
+
+	dialer := websocket.Dialer{HandshakeTimeout: wsHandshakeTimeout, TLSClientConfig: c.settings.tlsConfig()}
+	conn, resp, err := dialer.Dial(streamURL, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("%v (status %d)", err.Error(), resp.StatusCode)
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+// eventsError returns errCancelled for errors caused by the client being released
+func (c *VapixClient) eventsError(err error) error {
+	select {
+	case <-c.stop:
+		return errCancelled
+	default:
+		return fmt.Errorf("listenForEvents: %v", err.Error())
+	}
+}
+
+// eventsConfigureRequest subscribes to the topics of the resources
+func eventsConfigureRequest(resources []eventResource) eventsRequest {
+	req := eventsRequest{APIVersion: eventsAPIVersion, Method: eventsConfigure}
+
+	topics := make(map[string]bool)
+	for _, er := range resources {
+		if !topics[er.topic] {
+			topics[er.topic] = true
+			req.Params.EventFilterList = append(req.Params.EventFilterList, eventFilter{TopicFilter: er.topic})
+		}
+	}
+	return req
+}
+
+// eventCommandValues returns the readings of the resources mapping a notification
+func (c *VapixClient) eventCommandValues(events []eventResource, msg eventsMessage) []*sdkModels.CommandValue {
+	notification := msg.Params.Notification
+
+	var cvs []*sdkModels.CommandValue
+	for _, er := range events {
+		if !eventtopic.Matches(notification.Topic, er.topic) {
+			continue
+		}
+		if er.source != "" && !containsValue(notification.Message.Source, er.source) && !containsValue(notification.Message.Key, er.source) {
+			continue
+		}
+		value, ok := notification.Message.Data[er.item]
+		if !ok {
+			continue
+		}
+
+		cv, err := reading.FromString(er.resource.Name, er.resource.Properties.ValueType, fmt.Sprint(value), axisValues)
+		if err != nil {
+			c.lc.Warnf("Unable to convert Axis event item '%s' for resource '%s': %s", er.item, er.resource.Name, err.Error())
+			continue
+		}
+		cv.Origin = time.Now().UnixNano() / int64(time.Millisecond)
+		cvs = append(cvs, cv)
+	}
+	return cvs
+}
+
+func containsValue(items map[string]interface{}, value string) bool {
+	for _, v := range items {
+		if fmt.Sprint(v) == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package axis
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/gorilla/websocket"
+)
+
+func testProfile() models.DeviceProfile {
+	return models.DeviceProfile{DeviceResources: []models.DeviceResource{
+		{
+			Name:       "MotionDetected",
+			Attributes: map[string]interface{}{"axis_event_topic": "tns1:VideoSource/MotionAlarm", "axis_event_item": "State"},
+			Properties: models.ResourceProperties{ValueType: common.ValueTypeBool},
+		},
+		{
+			Name:       "Input1",
+			Attributes: map[string]interface{}{"axis_event_topic": "tns1:Device/tnsaxis:IO/Port", "axis_event_item": "state", "axis_event_source": "0"},
+			Properties: models.ResourceProperties{ValueType: common.ValueTypeBool},
+		},
+	}}
+}
+
+func TestListenForEvents(t *testing.T) {
+	configured := make(chan eventsRequest, 1)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/axis-cgi/wssession.cgi":
+			_, _ = w.Write([]byte("token123\n"))
+			return
+		case "/vapix/ws-data-stream":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("wssession") != "token123" || r.URL.Query().Get("sources") != "events" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var req eventsRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		configured <- req
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"apiVersion":"1.0","method":"events:configure"}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"apiVersion":"1.0","method":"events:notify","params":{"notification":`+
+			`{"topic":"tns1:Device/tnsaxis:IO/Port","timestamp":1622548800000,"message":{"source":{"port":"1"},"key":{},"data":{"state":"1"}}}}}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"apiVersion":"1.0","method":"events:notify","params":{"notification":`+
+			`{"topic":"tns1:VideoSource/MotionAlarm","timestamp":1622548800000,"message":{"source":{"VideoSourceConfigurationToken":"0"},"key":{},"data":{"State":"1"}}}}}`))
+
+		// hold the connection until the client closes it
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	asyncCh := make(chan *sdkModels.AsyncValues, 2)
+	c := NewClient(asyncCh, logger.NewMockClient()).(*VapixClient)
+	c.CameraInit(models.Device{Name: "Camera001"}, testProfile(), strings.TrimPrefix(server.URL, "http://"), "root", "pass")
+
+	select {
+	case req := <-configured:
+		reqJSON, _ := json.Marshal(req)
+		expected := `{"apiVersion":"1.0","method":"events:configure","params":{"eventFilterList":` +
+			`[{"topicFilter":"tns1:VideoSource/MotionAlarm"},{"topicFilter":"tns1:Device/tnsaxis:IO/Port"}]}}`
+		if string(reqJSON) != expected {
+			t.Errorf("Expected %s, Received %s", expected, reqJSON)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("No events:configure request")
+	}
+
+	// the port 1 notification doesn't match Input1's source, so only motion is read
+	select {
+	case av := <-asyncCh:
+		if av.DeviceName != "Camera001" || len(av.CommandValues) != 1 || av.CommandValues[0].DeviceResourceName != "MotionDetected" || av.CommandValues[0].Value != true {
+			t.Errorf("Unexpected async values: %+v", av.CommandValues[0])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("No async values for the motion notification")
+	}
+
+	released := make(chan bool)
+	go func() {
+		c.CameraRelease(false)
+		close(released)
+	}()
+	select {
+	case <-released:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Event stream not released")
+	}
+}
@@ -0,0 +1,147 @@
+package axis
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+const (
+	vapixPortFmtURL = "%s/axis-cgi/io/port.cgi?%s"
+
+	// ioPortTopic is the event topic of I/O port state changes, whose port source item numbers
+	// the ports from 0
+	ioPortTopic = "tns1:Device/tnsaxis:IO/Port"
+	ioPortItem  = "state"
+)
+
+// ioPortAttribute returns the port number, from 1, of the resource's axis_io_input or
+// axis_io_output attribute
+func ioPortAttribute(attributes map[string]interface{}) (int, bool, error) {
+	for _, attribute := range []string{"axis_io_input", "axis_io_output"} {
+		value, ok := attributes[attribute].(string)
+		if !ok {
+			continue
+		}
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 {
+			return 0, false, fmt.Errorf("vapix: invalid %s %s", attribute, value)
+		}
+		return port, attribute == "axis_io_output", nil
+	}
+	return 0, false, fmt.Errorf("vapix: resource has no I/O port")
+}
+
+func isPortResource(attributes map[string]interface{}) bool {
+	_, input := attributes["axis_io_input"].(string)
+	_, output := attributes["axis_io_output"].(string)
+	return input || output
+}
+
+// ioInputResources returns event resources for the input ports of resources with an
+// axis_io_input attribute, so that their state changes are sent as async readings
+func ioInputResources(profile models.DeviceProfile) []eventResource {
+	var resources []eventResource
+	for _, dr := range profile.DeviceResources {
+		if _, ok := dr.Attributes["axis_io_input"].(string); !ok {
+			continue
+		}
+		port, _, err := ioPortAttribute(dr.Attributes)
+		if err != nil {
+			continue
+		}
+
+		resources = append(resources, eventResource{resource: dr, topic: ioPortTopic, item: ioPortItem, source: strconv.Itoa(port - 1)})
+	}
+	return resources
+}
+
+// readPort reads whether an input or output port is active
+func (c *VapixClient) readPort(req sdkModels.CommandRequest) (*sdkModels.CommandValue, error) {
+	port, _, err := ioPortAttribute(req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"checkactive": {strconv.Itoa(port)}}
+	body, err := getBody(c.client, fmt.Sprintf(vapixPortFmtURL, c.baseURL, query.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("vapix: reading port %d: %v", port, err.Error())
+	}
+
+	active, err := parsePortState(string(body), port)
+	if err != nil {
+		return nil, err
+	}
+	return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeBool, active)
+}
+
+// writePort sets an output port active or inactive.  Activating a resource with an
+// axis_io_pulse attribute, e.g. "500ms", pulses the port, which returns to inactive after the
+// duration, e.g. to open a gate.
+func (c *VapixClient) writePort(req sdkModels.CommandRequest, param *sdkModels.CommandValue) error {
+	port, output, err := ioPortAttribute(req.Attributes)
+	if err != nil {
+		return err
+	}
+	if !output {
+		return fmt.Errorf("vapix: port %d is an input", port)
+	}
+
+	active, err := param.BoolValue()
+	if err != nil {
+		return fmt.Errorf("vapix: port %d requires a Bool value", port)
+	}
+
+	var pulse time.Duration
+	if value, ok := req.Attributes["axis_io_pulse"].(string); ok && value != "" {
+		pulse, err = time.ParseDuration(value)
+		if err != nil || pulse < time.Millisecond {
+			return fmt.Errorf("vapix: invalid axis_io_pulse %s", value)
+		}
+	}
+
+	query := url.Values{"action": {portAction(port, active, pulse)}}
+	_, err = getBody(c.client, fmt.Sprintf(vapixPortFmtURL, c.baseURL, query.Encode()))
+	if err != nil {
+		return fmt.Errorf("vapix: setting port %d: %v", port, err.Error())
+	}
+	return nil
+}
+
+// portAction returns the port.cgi action setting a port active ("/") or inactive ("\"), e.g.
+// "1:/" or, for a pulse, "1:/500\" which waits 500 milliseconds before setting it inactive
+func portAction(port int, active bool, pulse time.Duration) string {
+	if !active {
+		return fmt.Sprintf("%d:\\", port)
+	}
+	if pulse > 0 {
+		return fmt.Sprintf("%d:/%d\\", port, pulse.Milliseconds())
+	}
+	return fmt.Sprintf("%d:/", port)
+}
+
+// parsePortState parses the "port1=active" lines returned by port.cgi?checkactive
+func parsePortState(body string, port int) (bool, error) {
+	name := fmt.Sprintf("port%d", port)
+	for _, line := range strings.Split(body, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found || key != name {
+			continue
+		}
+		switch value {
+		case "active":
+			return true, nil
+		case "inactive":
+			return false, nil
+		}
+		return false, fmt.Errorf("vapix: unexpected state %s of port %d", value, port)
+	}
+	return false, fmt.Errorf("vapix: state of port %d not reported: %s", port, strings.TrimSpace(body))
+}
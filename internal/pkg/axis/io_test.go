@@ -0,0 +1,76 @@
+package axis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+func TestPortAction(t *testing.T) {
+	tests := []struct {
+		port     int
+		active   bool
+		pulse    time.Duration
+		expected string
+	}{
+		{1, true, 0, "1:/"},
+		{2, false, 0, "2:\\"},
+		{1, true, 500 * time.Millisecond, "1:/500\\"},
+		{1, false, time.Second, "1:\\"},
+	}
+
+	for _, test := range tests {
+		if action := portAction(test.port, test.active, test.pulse); action != test.expected {
+			t.Errorf("Expected %s, Received %s", test.expected, action)
+		}
+	}
+}
+
+func TestParsePortState(t *testing.T) {
+	tests := []struct {
+		body     string
+		port     int
+		expected bool
+		err      bool
+	}{
+		{"port1=active\n", 1, true, false},
+		{"port1=active\nport2=inactive\n", 2, false, false},
+		{"port1=unknown\n", 1, false, true},
+		{"# Error: Invalid port\n", 3, false, true},
+	}
+
+	for _, test := range tests {
+		active, err := parsePortState(test.body, test.port)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: Expected error", test.body)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: Unexpected error: %v", test.body, err)
+			continue
+		}
+		if active != test.expected {
+			t.Errorf("%q: Expected %v, Received %v", test.body, test.expected, active)
+		}
+	}
+}
+
+func TestIOInputResources(t *testing.T) {
+	profile := models.DeviceProfile{DeviceResources: []models.DeviceResource{
+		{Name: "Gate", Attributes: map[string]interface{}{"axis_io_output": "1"}},
+		{Name: "Doorbell", Attributes: map[string]interface{}{"axis_io_input": "2"}},
+		{Name: "Invalid", Attributes: map[string]interface{}{"axis_io_input": "0"}},
+	}}
+
+	resources := ioInputResources(profile)
+	if len(resources) != 1 {
+		t.Fatalf("Expected 1 input resource, Received %d", len(resources))
+	}
+	er := resources[0]
+	if er.resource.Name != "Doorbell" || er.topic != ioPortTopic || er.item != ioPortItem || er.source != "1" {
+		t.Errorf("Unexpected input resource %+v", er)
+	}
+}
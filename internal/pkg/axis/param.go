@@ -0,0 +1,182 @@
+package axis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/client"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/reading"
+)
+
+const vapixParamFmtURL = "%s/axis-cgi/param.cgi?%s"
+
+// axisValues converts parameter and event values, whose booleans Axis cameras report as yes/no,
+// on/off, true/false or 1/0
+var axisValues = reading.Options{VendorBooleans: true}
+
+// GetMotionRegions lists the parameters of the group given by the resource's axis_param_group
+// attribute, e.g. the "Motion" group holding the motion detection windows, as a JSON object.
+// client.ErrUnsupported is returned for resources without one, so that the regions are read
+// via ONVIF instead.
+func (c *VapixClient) GetMotionRegions(req sdkModels.CommandRequest) (string, error) {
+	group, ok := req.Attributes["axis_param_group"].(string)
+	if !ok {
+		return "", client.ErrUnsupported
+	}
+
+	params, err := c.listParams(group)
+	if err != nil {
+		return "", err
+	}
+
+	regions, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+
+	return string(regions), nil
+}
+
+// SetMotionRegions updates the parameters given as a JSON object of parameter names and values
+func (c *VapixClient) SetMotionRegions(req sdkModels.CommandRequest, regionsJSON string) error {
+	if _, ok := req.Attributes["axis_param_group"].(string); !ok {
+		return client.ErrUnsupported
+	}
+
+	var params map[string]string
+	err := json.Unmarshal([]byte(regionsJSON), &params)
+	if err != nil {
+		return fmt.Errorf("vapix: error unmarshaling motion regions: %v", err.Error())
+	}
+
+	return c.updateParams(params)
+}
+
+// readParam reads the parameter given by the resource's axis_param attribute, e.g.
+// "Image.I0.Appearance.Resolution", converted to the resource's value type.  Resources of value
+// type Object read every parameter of the group given instead, e.g. "Network".
+func (c *VapixClient) readParam(req sdkModels.CommandRequest) (*sdkModels.CommandValue, error) {
+	name, _ := req.Attributes["axis_param"].(string)
+
+	params, err := c.listParams(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Type == common.ValueTypeObject {
+		return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeObject, params)
+	}
+
+	value, ok := lookupParam(params, name)
+	if !ok {
+		return nil, fmt.Errorf("vapix: parameter %s not found", name)
+	}
+
+	cv, err := reading.FromString(req.DeviceResourceName, req.Type, value, axisValues)
+	if err != nil {
+		return nil, fmt.Errorf("vapix: converting parameter %s: %v", name, err.Error())
+	}
+	return cv, nil
+}
+
+// writeParam updates the parameter given by the resource's axis_param attribute
+func (c *VapixClient) writeParam(req sdkModels.CommandRequest, param *sdkModels.CommandValue) error {
+	name, _ := req.Attributes["axis_param"].(string)
+
+	value, err := paramValue(param)
+	if err != nil {
+		return err
+	}
+
+	return c.updateParams(map[string]string{name: value})
+}
+
+// lookupParam returns a parameter by its name, which param.cgi reports prefixed by "root."
+func lookupParam(params map[string]string, name string) (string, bool) {
+	if value, ok := params[name]; ok {
+		return value, true
+	}
+	value, ok := params["root."+name]
+	return value, ok
+}
+
+// paramValue formats a value written to a parameter.  Booleans are written as "yes" and "no",
+// as used by most Axis parameters.
+func paramValue(param *sdkModels.CommandValue) (string, error) {
+	switch param.Type {
+	case common.ValueTypeBool:
+		b, err := param.BoolValue()
+		if err != nil {
+			return "", err
+		}
+		if b {
+			return "yes", nil
+		}
+		return "no", nil
+	case common.ValueTypeString:
+		return param.StringValue()
+	case common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64,
+		common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64,
+		common.ValueTypeFloat32, common.ValueTypeFloat64:
+		return fmt.Sprint(param.Value), nil
+	}
+
+	return "", fmt.Errorf("vapix: unsupported parameter value type %s", param.Type)
+}
+
+// listParams returns the parameters of a group as reported by param.cgi?action=list
+func (c *VapixClient) listParams(group string) (map[string]string, error) {
+	query := url.Values{"action": {"list"}, "group": {group}}
+
+	body, err := getBody(c.client, fmt.Sprintf(vapixParamFmtURL, c.baseURL, query.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("vapix: listing parameters: %v", err.Error())
+	}
+
+	return parseParams(string(body))
+}
+
+// updateParams sets parameters with param.cgi?action=update
+func (c *VapixClient) updateParams(params map[string]string) error {
+	query := url.Values{"action": {"update"}}
+	for name, value := range params {
+		query.Set(name, value)
+	}
+
+	body, err := getBody(c.client, fmt.Sprintf(vapixParamFmtURL, c.baseURL, query.Encode()))
+	if err != nil {
+		return fmt.Errorf("vapix: updating parameters: %v", err.Error())
+	}
+
+	if reply := strings.TrimSpace(string(body)); reply != "OK" {
+		return fmt.Errorf("vapix: updating parameters: %s", reply)
+	}
+
+	return nil
+}
+
+// parseParams parses the name=value lines returned by param.cgi
+func parseParams(body string) (map[string]string, error) {
+	params := make(map[string]string)
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			return nil, fmt.Errorf("vapix: %s", strings.TrimSpace(strings.TrimPrefix(line, "#")))
+		}
+
+		name, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		params[name] = value
+	}
+	return params, nil
+}
@@ -0,0 +1,93 @@
+package axis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+func TestParamValue(t *testing.T) {
+	tests := []struct {
+		value     interface{}
+		valueType string
+		expected  string
+	}{
+		{true, common.ValueTypeBool, "yes"},
+		{false, common.ValueTypeBool, "no"},
+		{uint16(554), common.ValueTypeUint16, "554"},
+		{float32(1.5), common.ValueTypeFloat32, "1.5"},
+		{"1280x720", common.ValueTypeString, "1280x720"},
+	}
+
+	for _, test := range tests {
+		param, err := sdkModels.NewCommandValue("Param", test.valueType, test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, err := paramValue(param)
+		if err != nil {
+			t.Errorf("%v: Unexpected error: %v", test.value, err)
+			continue
+		}
+		if value != test.expected {
+			t.Errorf("%v: Expected %s, Received %s", test.value, test.expected, value)
+		}
+	}
+}
+
+func TestReadWriteParam(t *testing.T) {
+	var updated string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch query.Get("action") {
+		case "list":
+			if query.Get("group") != "Network.UPnP.Enabled" {
+				_, _ = w.Write([]byte("# Error: Error -1 getting param in group '" + query.Get("group") + "'\n"))
+				return
+			}
+			_, _ = w.Write([]byte("root.Network.UPnP.Enabled=yes\n"))
+		case "update":
+			updated = r.URL.RawQuery
+			_, _ = w.Write([]byte("OK\n"))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(nil, logger.NewMockClient()).(*VapixClient)
+	c.CameraInit(models.Device{Name: "Camera001"}, models.DeviceProfile{}, strings.TrimPrefix(server.URL, "http://"), "root", "pass")
+	defer c.CameraRelease(false)
+
+	req := sdkModels.CommandRequest{
+		DeviceResourceName: "UPnPEnabled",
+		Attributes:         map[string]interface{}{"axis_param": "Network.UPnP.Enabled"},
+		Type:               common.ValueTypeBool,
+	}
+
+	cv, err := c.HandleReadCommand(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cv.Value != true {
+		t.Errorf("Expected true, Received %v", cv.Value)
+	}
+
+	param, _ := sdkModels.NewCommandValue("UPnPEnabled", common.ValueTypeBool, false)
+	err = c.HandleWriteCommand(req, param)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated != "Network.UPnP.Enabled=no&action=update" {
+		t.Errorf("Unexpected update query %s", updated)
+	}
+
+	req.Attributes["axis_param"] = "Network.Missing"
+	if _, err = c.HandleReadCommand(req); err == nil {
+		t.Error("Expected error for a missing parameter")
+	}
+}
@@ -0,0 +1,271 @@
+package axis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+const (
+	vapixPTZFmtURL = "%s/axis-cgi/com/ptz.cgi?%s"
+
+	// values of the axis_ptz attribute
+	ptzContinuous = "continuous"
+	ptzAbsolute   = "absolute"
+	ptzPresetGoto = "preset_goto"
+	ptzPresetSet  = "preset_set"
+	ptzAutoFocus  = "autofocus"
+	ptzAutoIris   = "autoiris"
+	ptzPosition   = "position"
+
+	// ptzSpeedMaximum is the ptz.cgi speed of continuous moves at a speed of 1
+	ptzSpeedMaximum = 100
+)
+
+// ptzMove is the JSON parameter of continuous and absolute moves.  Continuous speeds range from
+// -1 to 1: positive values pan right, tilt up and zoom in, and 0 stops the movement.  Absolute
+// positions are pan and tilt angles in degrees and zoom steps from 1 to 9999; positions which
+// aren't given are kept.
+type ptzMove struct {
+	Pan  *float64
+	Tilt *float64
+	Zoom *float64
+}
+
+// ptzPositionReading is the reading of position resources
+type ptzPositionReading struct {
+	Pan       float64
+	Tilt      float64
+	Zoom      float64
+	Focus     float64
+	Iris      float64
+	AutoFocus bool
+	AutoIris  bool
+}
+
+// ptzQuery returns the ptz.cgi query for the command given by the resource's axis_ptz attribute:
+// continuous, absolute, preset_goto, preset_set, autofocus or autoiris.  The optional
+// axis_ptz_camera attribute selects the video channel of multi-channel devices.
+func ptzQuery(attributes map[string]interface{}, param *sdkModels.CommandValue) (url.Values, error) {
+	command, _ := attributes["axis_ptz"].(string)
+	query, err := ptzCameraQuery(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch command {
+	case ptzContinuous, ptzAbsolute:
+		var move ptzMove
+		s, err := param.StringValue()
+		if err != nil {
+			return nil, fmt.Errorf("vapix: %s command requires a JSON String value", command)
+		}
+		err = json.Unmarshal([]byte(s), &move)
+		if err != nil {
+			return nil, fmt.Errorf("vapix: error unmarshaling %s move: %v", command, err.Error())
+		}
+
+		if command == ptzContinuous {
+			err = continuousQuery(query, move)
+		} else {
+			err = absoluteQuery(query, move)
+		}
+		if err != nil {
+			return nil, err
+		}
+	case ptzPresetGoto, ptzPresetSet:
+		name, err := param.StringValue()
+		if err != nil || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("vapix: %s command requires a preset name", command)
+		}
+		if command == ptzPresetGoto {
+			query.Set("gotoserverpresetname", name)
+		} else {
+			query.Set("setserverpresetname", name)
+		}
+	case ptzAutoFocus, ptzAutoIris:
+		on, err := param.BoolValue()
+		if err != nil {
+			return nil, fmt.Errorf("vapix: %s command requires a Bool value", command)
+		}
+		query.Set(command, "off")
+		if on {
+			query.Set(command, "on")
+		}
+	default:
+		return nil, fmt.Errorf("vapix: unsupported axis_ptz %s", command)
+	}
+
+	return query, nil
+}
+
+func ptzCameraQuery(attributes map[string]interface{}) (url.Values, error) {
+	query := url.Values{}
+	if camera, ok := attributes["axis_ptz_camera"].(string); ok && camera != "" {
+		n, err := strconv.Atoi(camera)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("vapix: invalid axis_ptz_camera %s", camera)
+		}
+		query.Set("camera", camera)
+	}
+	return query, nil
+}
+
+func continuousQuery(query url.Values, move ptzMove) error {
+	var speeds [3]int
+	for i, speed := range []*float64{move.Pan, move.Tilt, move.Zoom} {
+		if speed == nil {
+			continue
+		}
+		if *speed < -1 || *speed > 1 {
+			return fmt.Errorf("vapix: speed %v out of range", *speed)
+		}
+		speeds[i] = int(math.Round(*speed * ptzSpeedMaximum))
+	}
+
+	if move.Pan == nil && move.Tilt == nil && move.Zoom == nil {
+		return fmt.Errorf("vapix: continuous move requires Pan, Tilt or Zoom")
+	}
+	if move.Pan != nil || move.Tilt != nil {
+		query.Set("continuouspantiltmove", fmt.Sprintf("%d,%d", speeds[0], speeds[1]))
+	}
+	if move.Zoom != nil {
+		query.Set("continuouszoommove", strconv.Itoa(speeds[2]))
+	}
+	return nil
+}
+
+func absoluteQuery(query url.Values, move ptzMove) error {
+	moved := false
+	if move.Pan != nil {
+		if *move.Pan < -180 || *move.Pan > 180 {
+			return fmt.Errorf("vapix: pan %v out of range", *move.Pan)
+		}
+		query.Set("pan", strconv.FormatFloat(*move.Pan, 'f', -1, 64))
+		moved = true
+	}
+	if move.Tilt != nil {
+		if *move.Tilt < -180 || *move.Tilt > 180 {
+			return fmt.Errorf("vapix: tilt %v out of range", *move.Tilt)
+		}
+		query.Set("tilt", strconv.FormatFloat(*move.Tilt, 'f', -1, 64))
+		moved = true
+	}
+	if move.Zoom != nil {
+		if *move.Zoom < 1 || *move.Zoom > 9999 {
+			return fmt.Errorf("vapix: zoom %v out of range", *move.Zoom)
+		}
+		query.Set("zoom", strconv.Itoa(int(math.Round(*move.Zoom))))
+		moved = true
+	}
+	if !moved {
+		return fmt.Errorf("vapix: absolute move requires Pan, Tilt or Zoom")
+	}
+	return nil
+}
+
+// ptzCommand sends a PTZ command to the camera
+func (c *VapixClient) ptzCommand(req sdkModels.CommandRequest, param *sdkModels.CommandValue) error {
+	query, err := ptzQuery(req.Attributes, param)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.ptzRequest(query)
+	return err
+}
+
+// readPTZPosition reads the camera's position, as an object for resources of value type Object
+// and as JSON otherwise
+func (c *VapixClient) readPTZPosition(req sdkModels.CommandRequest) (*sdkModels.CommandValue, error) {
+	query, err := ptzCameraQuery(req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	query.Set("query", "position")
+
+	body, err := c.ptzRequest(query)
+	if err != nil {
+		return nil, err
+	}
+
+	position, err := parsePTZPosition(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Type == common.ValueTypeObject {
+		return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeObject, position)
+	}
+
+	positionJSON, err := json.Marshal(position)
+	if err != nil {
+		return nil, err
+	}
+	return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeString, string(positionJSON))
+}
+
+// ptzRequest sends a ptz.cgi request, which replies to commands with 204 No Content and reports
+// errors in the body of a 200 OK
+func (c *VapixClient) ptzRequest(query url.Values) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(vapixPTZFmtURL, c.baseURL, query.Encode()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("vapix: new request GET Error: %v", err.Error())
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vapix: ptz GET Error: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return nil, fmt.Errorf("vapix: ptz status Error: %v", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("vapix: ptz read Error: %v", err.Error())
+	}
+	if reply := strings.TrimSpace(string(body)); strings.HasPrefix(reply, "Error") {
+		return nil, fmt.Errorf("vapix: ptz: %s", reply)
+	}
+	return body, nil
+}
+
+// parsePTZPosition parses the name=value lines returned by ptz.cgi?query=position
+func parsePTZPosition(body string) (ptzPositionReading, error) {
+	var position ptzPositionReading
+
+	values, err := parseParams(body)
+	if err != nil {
+		return position, err
+	}
+	if _, ok := values["pan"]; !ok {
+		return position, fmt.Errorf("vapix: no position reported: %s", strings.TrimSpace(body))
+	}
+
+	for name, value := range map[string]*float64{
+		"pan": &position.Pan, "tilt": &position.Tilt, "zoom": &position.Zoom, "focus": &position.Focus, "iris": &position.Iris,
+	} {
+		v, ok := values[name]
+		if !ok {
+			continue
+		}
+		*value, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			return position, fmt.Errorf("vapix: invalid %s %s", name, v)
+		}
+	}
+	position.AutoFocus = values["autofocus"] == "on"
+	position.AutoIris = values["autoiris"] == "on"
+
+	return position, nil
+}
@@ -0,0 +1,70 @@
+package axis
+
+import (
+	"testing"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+func TestPTZQuery(t *testing.T) {
+	tests := []struct {
+		attributes map[string]interface{}
+		valueType  string
+		value      interface{}
+		expected   string
+		err        bool
+	}{
+		{map[string]interface{}{"axis_ptz": "continuous"}, common.ValueTypeString, `{"Pan":0.5,"Tilt":-0.25}`, "continuouspantiltmove=50%2C-25", false},
+		{map[string]interface{}{"axis_ptz": "continuous", "axis_ptz_camera": "2"}, common.ValueTypeString, `{"Zoom":-1}`, "camera=2&continuouszoommove=-100", false},
+		{map[string]interface{}{"axis_ptz": "continuous"}, common.ValueTypeString, `{"Pan":1.5}`, "", true},
+		{map[string]interface{}{"axis_ptz": "continuous"}, common.ValueTypeString, `{}`, "", true},
+		{map[string]interface{}{"axis_ptz": "absolute"}, common.ValueTypeString, `{"Pan":-90.5,"Zoom":2500}`, "pan=-90.5&zoom=2500", false},
+		{map[string]interface{}{"axis_ptz": "absolute"}, common.ValueTypeString, `{"Zoom":0}`, "", true},
+		{map[string]interface{}{"axis_ptz": "preset_goto"}, common.ValueTypeString, "Gate", "gotoserverpresetname=Gate", false},
+		{map[string]interface{}{"axis_ptz": "preset_set"}, common.ValueTypeString, "Home", "setserverpresetname=Home", false},
+		{map[string]interface{}{"axis_ptz": "autofocus"}, common.ValueTypeBool, true, "autofocus=on", false},
+		{map[string]interface{}{"axis_ptz": "autoiris"}, common.ValueTypeBool, false, "autoiris=off", false},
+		{map[string]interface{}{"axis_ptz": "autoiris", "axis_ptz_camera": "0"}, common.ValueTypeBool, false, "", true},
+		{map[string]interface{}{"axis_ptz": "spin"}, common.ValueTypeBool, true, "", true},
+	}
+
+	for _, test := range tests {
+		param, err := sdkModels.NewCommandValue("PTZ", test.valueType, test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		query, err := ptzQuery(test.attributes, param)
+		if test.err {
+			if err == nil {
+				t.Errorf("%v %v: Expected error", test.attributes, test.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v %v: Unexpected error: %v", test.attributes, test.value, err)
+			continue
+		}
+		if query.Encode() != test.expected {
+			t.Errorf("%v %v: Expected %s, Received %s", test.attributes, test.value, test.expected, query.Encode())
+		}
+	}
+}
+
+func TestParsePTZPosition(t *testing.T) {
+	body := "pan=-45.2\ntilt=-10.01\nzoom=1\niris=5000\nfocus=7400\nautofocus=on\nautoiris=off\n"
+
+	position, err := parsePTZPosition(body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := ptzPositionReading{Pan: -45.2, Tilt: -10.01, Zoom: 1, Focus: 7400, Iris: 5000, AutoFocus: true}
+	if position != expected {
+		t.Errorf("Expected %+v, Received %+v", expected, position)
+	}
+
+	if _, err = parsePTZPosition("Error: No PTZ driver\n"); err == nil {
+		t.Error("Expected error for a missing position")
+	}
+}
@@ -0,0 +1,134 @@
+package axis
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/client"
+)
+
+const (
+	vapixMJPEGFmtURL = "%s/axis-cgi/mjpg/video.cgi?%s"
+
+	httpProtocol       = "HTTP"
+	defaultMJPEGFps    = "1"
+	defaultMJPEGCamera = "1"
+
+	// values of the AxisHTTPS setting
+	httpsOn       = "true"
+	httpsInsecure = "insecure"
+)
+
+// settings are the optional per-device settings of the Axis client, given as properties of the
+// device's HTTP protocol:
+//
+//	AxisHTTPS: "true" to use HTTPS, or "insecure" to skip the verification of the camera's certificate
+//	AxisMJPEGFps: frame rate of the MJPEG streams triggers are read from, 1 by default
+//	AxisMJPEGResolution: resolution of the MJPEG streams, e.g. "320x240"
+//	AxisMJPEGCompression: compression of the MJPEG streams, from 0 to 100
+//	AxisMJPEGCameras: video channels to read triggers from, e.g. "1,2,3,4" for an encoder
+type settings struct {
+	https    bool
+	insecure bool
+
+	fps         string
+	resolution  string
+	compression string
+	cameras     []string
+}
+
+// settingsFromDevice returns the device's settings, or an error and the default settings if
+// any setting is invalid
+func settingsFromDevice(device models.Device) (settings, error) {
+	defaults := settings{fps: defaultMJPEGFps, cameras: []string{defaultMJPEGCamera}}
+	s := defaults
+	protocol := device.Protocols[httpProtocol]
+
+	switch https := protocol["AxisHTTPS"]; https {
+	case "", "false":
+	case httpsOn:
+		s.https = true
+	case httpsInsecure:
+		s.https, s.insecure = true, true
+	default:
+		return defaults, fmt.Errorf("vapix: invalid AxisHTTPS %s", https)
+	}
+
+	if fps := protocol["AxisMJPEGFps"]; fps != "" {
+		n, err := strconv.Atoi(fps)
+		if err != nil || n < 1 || n > 60 {
+			return defaults, fmt.Errorf("vapix: invalid AxisMJPEGFps %s", fps)
+		}
+		s.fps = fps
+	}
+
+	if resolution := protocol["AxisMJPEGResolution"]; resolution != "" {
+		if !client.ValidResolution(resolution) {
+			return defaults, fmt.Errorf("vapix: invalid AxisMJPEGResolution %s", resolution)
+		}
+		s.resolution = resolution
+	}
+
+	if compression := protocol["AxisMJPEGCompression"]; compression != "" {
+		n, err := strconv.Atoi(compression)
+		if err != nil || n < 0 || n > 100 {
+			return defaults, fmt.Errorf("vapix: invalid AxisMJPEGCompression %s", compression)
+		}
+		s.compression = compression
+	}
+
+	if cameras := protocol["AxisMJPEGCameras"]; cameras != "" {
+		s.cameras = nil
+		for _, camera := range strings.Split(cameras, ",") {
+			camera = strings.TrimSpace(camera)
+			n, err := strconv.Atoi(camera)
+			if err != nil || n < 1 {
+				return defaults, fmt.Errorf("vapix: invalid AxisMJPEGCameras %s", cameras)
+			}
+			s.cameras = append(s.cameras, camera)
+		}
+	}
+
+	return s, nil
+}
+
+// baseURL returns the URL of the camera at the address, by the scheme of the settings
+func (s settings) baseURL(address string) string {
+	if s.https {
+		return "https://" + address
+	}
+	return "http://" + address
+}
+
+// httpClient returns the HTTP client for requests to the camera
+func (s settings) httpClient() *http.Client {
+	if !s.insecure {
+		return &http.Client{}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: s.tlsConfig()}}
+}
+
+func (s settings) tlsConfig() *tls.Config {
+	if !s.insecure {
+		return nil
+	}
+	return &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+}
+
+// mjpegURL returns the video.cgi URL of the MJPEG stream of a video channel
+func (s settings) mjpegURL(baseURL string, camera string) string {
+	query := url.Values{"fps": {s.fps}, "camera": {camera}}
+	if s.resolution != "" {
+		query.Set("resolution", s.resolution)
+	}
+	if s.compression != "" {
+		query.Set("compression", s.compression)
+	}
+	return fmt.Sprintf(vapixMJPEGFmtURL, baseURL, query.Encode())
+}
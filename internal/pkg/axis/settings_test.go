@@ -0,0 +1,55 @@
+package axis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+func TestSettingsFromDevice(t *testing.T) {
+	defaults := settings{fps: "1", cameras: []string{"1"}}
+
+	tests := []struct {
+		protocol models.ProtocolProperties
+		expected settings
+		err      bool
+	}{
+		{models.ProtocolProperties{"Address": "10.0.0.1"}, defaults, false},
+		{
+			models.ProtocolProperties{"AxisHTTPS": "true", "AxisMJPEGFps": "5", "AxisMJPEGResolution": "320x240", "AxisMJPEGCompression": "50"},
+			settings{https: true, fps: "5", resolution: "320x240", compression: "50", cameras: []string{"1"}},
+			false,
+		},
+		{
+			models.ProtocolProperties{"AxisHTTPS": "insecure", "AxisMJPEGCameras": "1, 2,3,4"},
+			settings{https: true, insecure: true, fps: "1", cameras: []string{"1", "2", "3", "4"}},
+			false,
+		},
+		{models.ProtocolProperties{"AxisHTTPS": "yes"}, defaults, true},
+		{models.ProtocolProperties{"AxisMJPEGFps": "0"}, defaults, true},
+		{models.ProtocolProperties{"AxisMJPEGResolution": "VGA"}, defaults, true},
+		{models.ProtocolProperties{"AxisMJPEGCompression": "150"}, defaults, true},
+		{models.ProtocolProperties{"AxisMJPEGCameras": "1,quad"}, defaults, true},
+	}
+
+	for _, test := range tests {
+		device := models.Device{Protocols: map[string]models.ProtocolProperties{"HTTP": test.protocol}}
+		s, err := settingsFromDevice(device)
+		if test.err != (err != nil) {
+			t.Errorf("%v: Expected error %v, Received %v", test.protocol, test.err, err)
+		}
+		if !reflect.DeepEqual(s, test.expected) {
+			t.Errorf("%v: Expected %+v, Received %+v", test.protocol, test.expected, s)
+		}
+	}
+}
+
+func TestMJPEGURL(t *testing.T) {
+	s := settings{https: true, fps: "2", resolution: "640x480", compression: "30"}
+
+	expected := "https://10.0.0.1/axis-cgi/mjpg/video.cgi?camera=3&compression=30&fps=2&resolution=640x480"
+	if mjpegURL := s.mjpegURL(s.baseURL("10.0.0.1"), "3"); mjpegURL != expected {
+		t.Errorf("Expected %s, Received %s", expected, mjpegURL)
+	}
+}
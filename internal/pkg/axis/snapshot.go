@@ -0,0 +1,123 @@
+package axis
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/client"
+)
+
+const (
+	vapixImageFmtURL = "%s/axis-cgi/jpg/image.cgi"
+
+	// urlRawQuery is the attribute in which the device SDK passes the query parameters of a
+	// command request
+	urlRawQuery = "urlRawQuery"
+)
+
+// snapshotParams maps the snapshot attributes to their image.cgi parameters, which are also the
+// names of the request query parameters overriding them
+var snapshotParams = []struct {
+	attribute string
+	param     string
+}{
+	{"axis_snapshot_resolution", "resolution"},
+	{"axis_snapshot_compression", "compression"},
+	{"axis_snapshot_rotation", "rotation"},
+	{"axis_snapshot_camera", "camera"},
+}
+
+// snapshotURL returns the image.cgi URL of the JPEG snapshot described by the resource's
+// attributes: axis_snapshot_resolution, e.g. "640x480", axis_snapshot_compression, from 0 to 100,
+// axis_snapshot_rotation, 0, 90, 180 or 270 degrees, and axis_snapshot_camera, the video channel.
+// The parameters of the same names in the request's query, e.g. ?resolution=160x90 for a
+// thumbnail, override the attributes.  The camera's defaults are used for parameters which
+// aren't given.
+func snapshotURL(baseURL string, attributes map[string]interface{}) (string, error) {
+	var requested url.Values
+	if rawQuery, ok := attributes[urlRawQuery].(string); ok && rawQuery != "" {
+		var err error
+		requested, err = url.ParseQuery(rawQuery)
+		if err != nil {
+			return "", fmt.Errorf("vapix: invalid snapshot query %s", rawQuery)
+		}
+	}
+
+	query := url.Values{}
+	for _, sp := range snapshotParams {
+		value, _ := attributes[sp.attribute].(string)
+		if v := requested.Get(sp.param); v != "" {
+			value = v
+		}
+		if value == "" {
+			continue
+		}
+		if !validSnapshotParam(sp.param, value) {
+			return "", fmt.Errorf("vapix: invalid snapshot %s %s", sp.param, value)
+		}
+		query.Set(sp.param, value)
+	}
+
+	snapURL := fmt.Sprintf(vapixImageFmtURL, baseURL)
+	if len(query) > 0 {
+		snapURL += "?" + query.Encode()
+	}
+	return snapURL, nil
+}
+
+func validSnapshotParam(param string, value string) bool {
+	if param == "resolution" {
+		return client.ValidResolution(value)
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return false
+	}
+	switch param {
+	case "compression":
+		return n >= 0 && n <= 100
+	case "rotation":
+		return n == 0 || n == 90 || n == 180 || n == 270
+	}
+	return n >= 1
+}
+
+// readSnapshot reads a JPEG snapshot from the camera's image.cgi, without the ONVIF
+// GetSnapshotUri round trip
+func (c *VapixClient) readSnapshot(req sdkModels.CommandRequest) (*sdkModels.CommandValue, error) {
+	snapURL, err := snapshotURL(c.baseURL, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodGet, snapURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("vapix: new request GET Error: %v", err.Error())
+	}
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("vapix: snapshot GET Error: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vapix: snapshot status Error: %v", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/jpeg") {
+		return nil, fmt.Errorf("vapix: unexpected snapshot content type %s", contentType)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("vapix: snapshot read Error: %v", err.Error())
+	}
+	return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeBinary, data)
+}
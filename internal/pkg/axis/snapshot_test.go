@@ -0,0 +1,46 @@
+package axis
+
+import (
+	"testing"
+)
+
+func TestSnapshotURL(t *testing.T) {
+	tests := []struct {
+		attributes map[string]interface{}
+		expected   string
+		err        bool
+	}{
+		{map[string]interface{}{"axis_snapshot": "jpeg"}, "http://10.0.0.1/axis-cgi/jpg/image.cgi", false},
+		{
+			map[string]interface{}{"axis_snapshot": "jpeg", "axis_snapshot_resolution": "1280x720", "axis_snapshot_compression": "30", "axis_snapshot_camera": "2"},
+			"http://10.0.0.1/axis-cgi/jpg/image.cgi?camera=2&compression=30&resolution=1280x720",
+			false,
+		},
+		{
+			map[string]interface{}{"axis_snapshot": "jpeg", "axis_snapshot_resolution": "1280x720", "urlRawQuery": "resolution=160x90&rotation=180&ds-pushevent=no"},
+			"http://10.0.0.1/axis-cgi/jpg/image.cgi?resolution=160x90&rotation=180",
+			false,
+		},
+		{map[string]interface{}{"axis_snapshot_resolution": "large"}, "", true},
+		{map[string]interface{}{"axis_snapshot_compression": "101"}, "", true},
+		{map[string]interface{}{"urlRawQuery": "rotation=45"}, "", true},
+		{map[string]interface{}{"axis_snapshot_camera": "0"}, "", true},
+	}
+
+	for _, test := range tests {
+		snapURL, err := snapshotURL("http://10.0.0.1", test.attributes)
+		if test.err {
+			if err == nil {
+				t.Errorf("%v: Expected error", test.attributes)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: Unexpected error: %v", test.attributes, err)
+			continue
+		}
+		if snapURL != test.expected {
+			t.Errorf("%v: Expected %s, Received %s", test.attributes, test.expected, snapURL)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
@@ -21,8 +22,6 @@ import (
 	"github.com/edgexfoundry/device-camera-go/internal/pkg/digest"
 )
 
-const vapixFmtURL = "http://%s/axis-cgi/mjpg/video.cgi?fps=1" // hard coded to mjpg video for now
-
 var errCancelled = errors.New("cancelled")
 
 type trigger struct {
@@ -30,14 +29,22 @@ type trigger struct {
 	state     bool
 }
 
-// VapixClient is a client for requesting some basic analytic events from Axis cameras.
-// It uses some deprecated APIs/methods and might not work with all Axis cameras out of the box.
+// VapixClient is a client for requesting analytic events from Axis cameras.  Events mapped by
+// axis_event_topic and axis_aoa_scenario attributes are received from the WebSocket event stream; the alarm codes of
+// alarm_code attributes are read from the comments of an MJPEG stream, a legacy mode relying on
+// deprecated firmware behaviour which might not work with all Axis cameras.
 type VapixClient struct {
 	lc        logger.LoggingClient
 	asyncChan chan<- *sdkModels.AsyncValues
+	client    digest.Client
+	baseURL   string
+	settings  settings
 
-	alarms      map[string]models.DeviceResource
-	alarmStates map[string]bool
+	// alarms maps alarm codes to their resources, which an axis_camera attribute limits to the
+	// triggers of one video channel
+	alarms    map[string][]models.DeviceResource
+	events    []eventResource
+	scenarios []scenarioResource
 
 	stop    chan bool
 	stopped chan bool
@@ -75,15 +82,19 @@ func (c *VapixClient) parseTriggers(bytes []byte) trigger {
 	return trigger{}
 }
 
-func (c *VapixClient) listenForTriggers(edgexDevice models.Device, address string, username string, password string) error {
-	dclient := digest.NewDClient(&http.Client{}, username, password)
-	url := fmt.Sprintf(vapixFmtURL, address)
+// listenForTriggers reads the triggers of a video channel from its MJPEG stream
+func (c *VapixClient) listenForTriggers(edgexDevice models.Device, camera string, username string, password string) error {
+	dclient := digest.NewDClient(c.settings.httpClient(), username, password)
+	url := c.settings.mjpegURL(c.baseURL, camera)
 
 	reader, err := getMultipartReader(dclient, url)
 	if err != nil {
-		return fmt.Errorf("listenForTriggers: %v", err.Error())
+		return fmt.Errorf("listenForTriggers: camera %s: %v", camera, err.Error())
 	}
 
+	// the states of the alarm codes of the channel, all inactive initially
+	alarmStates := make(map[string]bool)
+
 	for {
 		select {
 		case <-c.stop:
@@ -104,13 +115,20 @@ func (c *VapixClient) listenForTriggers(edgexDevice models.Device, address strin
 
 			t := c.parseTriggers(slurp)
 
-			if t.state != c.alarmStates[t.alarmCode] {
-				c.alarmStates[t.alarmCode] = t.state
-				cvs, err := c.getCommandValue(edgexDevice, c.alarms[t.alarmCode].Name, t.state)
-				if err != nil {
-					continue
+			if t.state != alarmStates[t.alarmCode] {
+				alarmStates[t.alarmCode] = t.state
+				for _, dr := range c.alarms[t.alarmCode] {
+					if resourceCamera, ok := dr.Attributes["axis_camera"].(string); ok && resourceCamera != camera {
+						continue
+					}
+					cvs, err := c.getCommandValue(edgexDevice, dr.Name, t.state)
+					if err != nil {
+						continue
+					}
+					if !c.sendEvent(edgexDevice, cvs) {
+						return errCancelled
+					}
 				}
-				c.sendEvent(edgexDevice, cvs)
 			}
 		}
 	}
@@ -123,51 +141,126 @@ func NewClient(asyncCh chan<- *sdkModels.AsyncValues, lc logger.LoggingClient) c
 
 // CameraInit initializes the Vapix listener for the camera
 func (c *VapixClient) CameraInit(edgexDevice models.Device, edgexProfile models.DeviceProfile, ipAddress string, username string, password string) {
-	if c.alarms == nil {
-		c.alarms = make(map[string]models.DeviceResource)
+	var err error
+	c.settings, err = settingsFromDevice(edgexDevice)
+	if err != nil {
+		c.lc.Errorf("Invalid settings of device '%s', using defaults: %s", edgexDevice.Name, err.Error())
+	}
+
+	if c.client == nil {
+		c.client = digest.NewDClient(c.settings.httpClient(), username, password)
 	}
 
-	if c.alarmStates == nil {
-		c.alarmStates = make(map[string]bool)
+	c.baseURL = c.settings.baseURL(ipAddress)
+
+	if c.alarms == nil {
+		c.alarms = make(map[string][]models.DeviceResource)
 	}
 
-	// interrogate device profile for alarms to listen for
+	// interrogate device profile for alarms and events to listen for
 	deviceResources := edgexProfile.DeviceResources
 
 	for _, e := range deviceResources {
 		alarmCode, ok := e.Attributes["alarm_code"].(string)
 		if ok {
-			c.alarms[alarmCode] = e
-			c.alarmStates[alarmCode] = false
+			c.alarms[alarmCode] = append(c.alarms[alarmCode], e)
 		}
 	}
+	c.events = append(eventResourcesFromProfile(edgexProfile), ioInputResources(edgexProfile)...)
+	c.scenarios = scenarioResourcesFromProfile(edgexProfile)
+
+	// the listeners are limited to the APIs of the camera if it reports them; otherwise all are
+	// started as for cameras whose firmware predates API discovery
+	inv, err := c.getInventory()
+	if err != nil {
+		c.lc.Warnf("Unable to discover the APIs of device '%s', enabling all listeners: %s", edgexDevice.Name, err.Error())
+	} else {
+		c.lc.Infof("Device '%s' is an Axis %s with firmware %s", edgexDevice.Name, inv.ProductNumber, inv.Firmware)
+		c.enableSupportedListeners(inv)
+	}
 
-	go retryLoop(func() error {
-		err := c.listenForTriggers(edgexDevice, ipAddress, username, password)
-		defer close(c.stopped)
-		return err
-	}, c.lc)
+	c.stop = make(chan bool)
+	c.stopped = make(chan bool)
+
+	var listeners sync.WaitGroup
+	if len(c.events) > 0 || len(c.scenarios) > 0 {
+		listeners.Add(1)
+		go func() {
+			defer listeners.Done()
+			retryLoop(func() error {
+				return c.listenForEvents(edgexDevice, username, password)
+			}, c.lc, c.stop)
+		}()
+	}
+	if len(c.alarms) > 0 {
+		// one listener per video channel of multi-channel encoders
+		for _, camera := range c.settings.cameras {
+			camera := camera
+			listeners.Add(1)
+			go func() {
+				defer listeners.Done()
+				retryLoop(func() error {
+					return c.listenForTriggers(edgexDevice, camera, username, password)
+				}, c.lc, c.stop)
+			}()
+		}
+	}
+
+	go func() {
+		listeners.Wait()
+		close(c.stopped)
+	}()
 }
 
-// HandleReadCommand is not implemented for Vapix--all commands that reach here are unexpected.
+// HandleReadCommand reads the parameter of resources with an axis_param attribute, the state of
+// I/O port resources, the PTZ position, JPEG snapshots, ACAP applications and the inventory
 func (c *VapixClient) HandleReadCommand(req sdkModels.CommandRequest) (*sdkModels.CommandValue, error) {
+	if _, ok := req.Attributes["axis_param"].(string); ok {
+		return c.readParam(req)
+	}
+	if isPortResource(req.Attributes) {
+		return c.readPort(req)
+	}
+	if command, ok := req.Attributes["axis_ptz"].(string); ok && command == ptzPosition {
+		return c.readPTZPosition(req)
+	}
+	if _, ok := req.Attributes["axis_snapshot"]; ok {
+		return c.readSnapshot(req)
+	}
+	if _, ok := req.Attributes["axis_acap"].(string); ok {
+		return c.readACAP(req)
+	}
+	if _, ok := req.Attributes["axis_inventory"]; ok {
+		return c.readInventory(req)
+	}
 	return nil, fmt.Errorf("vapix: unrecognized read command")
 }
 
-// HandleWriteCommand is not implemented for Vapix--all commands that reach here are unexpected.
+// HandleWriteCommand writes the parameter of resources with an axis_param attribute, sets output
+// port resources, sends PTZ commands and manages ACAP applications
 func (c *VapixClient) HandleWriteCommand(req sdkModels.CommandRequest, param *sdkModels.CommandValue) error {
+	if _, ok := req.Attributes["axis_param"].(string); ok {
+		return c.writeParam(req, param)
+	}
+	if isPortResource(req.Attributes) {
+		return c.writePort(req, param)
+	}
+	if _, ok := req.Attributes["axis_ptz"].(string); ok {
+		return c.ptzCommand(req, param)
+	}
+	if _, ok := req.Attributes["axis_acap"].(string); ok {
+		return c.writeACAP(req, param)
+	}
 	return fmt.Errorf("vapix: unrecognized write command")
 }
 
 // CameraRelease shuts down the Vapix listener
 func (c *VapixClient) CameraRelease(force bool) {
 	close(c.stop)
-	if !force {
-		<-c.stopped
-	}
+	client.WaitStopped(c.stopped, force)
 }
 
-func retryLoop(fn func() error, client logger.LoggingClient) {
+func retryLoop(fn func() error, client logger.LoggingClient, stop chan bool) {
 	for {
 		err := fn()
 		if err != nil {
@@ -176,7 +269,12 @@ func retryLoop(fn func() error, client logger.LoggingClient) {
 			}
 			client.Error(err.Error())
 		}
-		time.Sleep(5 * time.Second)
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(5 * time.Second):
+		}
 	}
 }
 
@@ -191,13 +289,36 @@ func (c *VapixClient) getCommandValue(edgexDevice models.Device, trigger string,
 	return cvs, nil
 }
 
-func (c *VapixClient) sendEvent(edgexDevice models.Device, cvs []*sdkModels.CommandValue) {
+// sendEvent sends the async readings unless the client is released.  Returns false when released.
+func (c *VapixClient) sendEvent(edgexDevice models.Device, cvs []*sdkModels.CommandValue) bool {
 	var av sdkModels.AsyncValues
 	av.DeviceName = edgexDevice.Name
 
 	av.CommandValues = append(av.CommandValues, cvs...)
 
-	c.asyncChan <- &av
+	return client.SendAsyncValues(c.asyncChan, c.stop, &av)
+}
+
+func getBody(client digest.Client, url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("New request GET Error: %v", err.Error())
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET Error: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status Error: %v", resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read Error: %v", err.Error())
+	}
+	return data, nil
 }
 
 func getMultipartReader(client digest.Client, url string) (*multipart.Reader, error) {
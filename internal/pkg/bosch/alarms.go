@@ -0,0 +1,67 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// alarmCatalogueEntry is an alarm the camera reports in its alarm overview.  Its Type is the
+// value to use as the alarm_type attribute of a device resource.
+type alarmCatalogueEntry struct {
+	ID       uint16
+	Source   uint16
+	Type     uint16
+	Name     string
+	Add      bool
+	Delete   bool
+	State    bool
+	StateSet bool
+}
+
+// getAlarmCatalogue reads the alarm overview of the camera and returns every alarm entry as JSON,
+// whether or not it is mapped to a device resource or active
+func (rc *RcpClient) getAlarmCatalogue() (string, error) {
+	result, err := rc.rcpCommand(confAlarmOverview, rcpTypeOctet, rcpDirRead, rcpDefaultLine, "")
+	if err != nil {
+		return "", err
+	}
+
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.ReplaceAll(result.Hex, " ", ""), "0x"))
+	if err != nil {
+		return "", fmt.Errorf("rcp: invalid hex result: %v", err.Error())
+	}
+
+	return alarmCatalogue(data)
+}
+
+func alarmCatalogue(overview []byte) (string, error) {
+	alarms, _ := parseAlarmEntries(overview)
+
+	entries := make([]alarmCatalogueEntry, 0, len(alarms))
+	for _, a := range alarms {
+		entries = append(entries, alarmCatalogueEntry{
+			ID:       a.EntryID,
+			Source:   a.AlarmSource,
+			Type:     a.AlarmType,
+			Name:     a.AlarmName,
+			Add:      a.FlagAdd,
+			Delete:   a.FlagDelete,
+			State:    a.FlagState,
+			StateSet: a.FlagStateSet,
+		})
+	}
+
+	catalogue, err := json.Marshal(entries)
+	if err != nil {
+		return "", err
+	}
+	return string(catalogue), nil
+}
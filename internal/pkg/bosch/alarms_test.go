@@ -0,0 +1,64 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"testing"
+)
+
+func TestAlarmCatalogue(t *testing.T) {
+	entry := func(id byte, flags byte, source byte, alarmType byte, name string) []byte {
+		b := []byte{0, id, 0, byte(8 + 2*len(name)), flags, 0, source, alarmType}
+		for _, r := range name {
+			b = append(b, 0, byte(r))
+		}
+		return b
+	}
+
+	// a readout holding an active motion alarm and an inactive input alarm
+	overview := append([]byte{0x80, 0, 0, 0}, entry(1, alarmStateFlag|alarmStateSetFlag, 1, 16, "Motion")...)
+	overview = append(overview, entry(2, 0, 2, 1, "In")...)
+
+	tests := []struct {
+		name     string
+		overview []byte
+		expected string
+	}{
+		{
+			name:     "readout",
+			overview: overview,
+			expected: `[{"ID":1,"Source":1,"Type":16,"Name":"Motion","Add":false,"Delete":false,"State":true,"StateSet":true},` +
+				`{"ID":2,"Source":2,"Type":1,"Name":"In","Add":false,"Delete":false,"State":false,"StateSet":false}]`,
+		},
+		{
+			name:     "truncated entry",
+			overview: overview[:len(overview)-2],
+			expected: `[{"ID":1,"Source":1,"Type":16,"Name":"Motion","Add":false,"Delete":false,"State":true,"StateSet":true}]`,
+		},
+		{
+			name:     "empty",
+			overview: []byte{0x80},
+			expected: `[]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			catalogue, err := alarmCatalogue(test.overview)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if catalogue != test.expected {
+				t.Errorf("Expected %v, Received %v", test.expected, catalogue)
+			}
+		})
+	}
+
+	if alarms := parseAlarms(overview); len(alarms) != 1 || alarms[0].AlarmType != 16 {
+		t.Errorf("Expected only the active alarm from a readout, Received %v", alarms)
+	}
+}
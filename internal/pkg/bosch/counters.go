@@ -0,0 +1,155 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+// values of the counter_value attribute of counter resources
+const (
+	counterTotal = "total"
+	counterDelta = "delta"
+	counterRate  = "rate"
+
+	defaultCounterInterval = time.Minute
+)
+
+// counterKey identifies a counter of a line, or of any line for resources without a line attribute
+type counterKey struct {
+	line int
+	name string
+}
+
+// counterState is the last reported value of an IVA counter, and its change since the value
+// reported before
+type counterState struct {
+	line    int
+	id      uint8
+	value   uint32
+	updated time.Time
+
+	// hasDelta is set once a second value has been reported
+	hasDelta bool
+	delta    uint32
+	elapsed  time.Duration
+}
+
+// update records a newly reported value.  A value below the previous one means the counter was
+// reset, so the delta is the value itself.
+func (s counterState) update(counter counterData, now time.Time) counterState {
+	next := counterState{id: counter.ID, value: counter.Value, updated: now}
+	if s.updated.IsZero() {
+		return next
+	}
+
+	next.hasDelta = true
+	next.elapsed = now.Sub(s.updated)
+	if counter.Value >= s.value {
+		next.delta = counter.Value - s.value
+	} else {
+		next.delta = counter.Value
+	}
+	return next
+}
+
+// rate is the delta scaled to the interval, e.g. people per 15 minutes
+func (s counterState) rate(interval time.Duration) float32 {
+	if s.elapsed <= 0 {
+		return 0
+	}
+	return float32(float64(s.delta) * float64(interval) / float64(s.elapsed))
+}
+
+// counterCommandValue returns the reading of a counter resource: by its counter_value attribute,
+// the total count, the delta since the previous report or the rate of that delta per
+// counter_interval, one minute by default.  Deltas and rates have no reading before the second
+// report, which is indicated by false.
+func counterCommandValue(dr models.DeviceResource, state counterState) (*sdkModels.CommandValue, bool, error) {
+	kind, _ := dr.Attributes["counter_value"].(string)
+
+	switch kind {
+	case "", counterTotal:
+		cv, err := sdkModels.NewCommandValue(dr.Name, common.ValueTypeUint32, state.value)
+		return cv, true, err
+	case counterDelta:
+		if !state.hasDelta {
+			return nil, false, nil
+		}
+		cv, err := sdkModels.NewCommandValue(dr.Name, common.ValueTypeUint32, state.delta)
+		return cv, true, err
+	case counterRate:
+		interval, err := counterInterval(dr.Attributes)
+		if err != nil {
+			return nil, false, err
+		}
+		if !state.hasDelta {
+			return nil, false, nil
+		}
+		cv, err := sdkModels.NewCommandValue(dr.Name, common.ValueTypeFloat32, state.rate(interval))
+		return cv, true, err
+	}
+
+	return nil, false, fmt.Errorf("rcp: unsupported counter_value %s", kind)
+}
+
+func counterInterval(attributes map[string]interface{}) (time.Duration, error) {
+	value, ok := attributes["counter_interval"].(string)
+	if !ok || value == "" {
+		return defaultCounterInterval, nil
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("rcp: invalid counter_interval %s", value)
+	}
+	return interval, nil
+}
+
+// resetCounter resets an IVA counter, given by the resource's counter_reset attribute, of the
+// resource's line to zero.  The counter is identified by the ID the camera reported it with, so it
+// must have been reported at least once.
+func (rc *RcpClient) resetCounter(attributes map[string]interface{}) error {
+	name, _ := attributes["counter_reset"].(string)
+
+	line, err := rcpNumAttribute(attributes)
+	if err != nil {
+		return fmt.Errorf("rcp: %v", err.Error())
+	}
+
+	state, ok := rc.getCounterState(line, name)
+	if !ok {
+		return fmt.Errorf("rcp: counter %s of line %d has not been reported by the camera yet", name, line)
+	}
+
+	command := confIvaCounterReset
+	if c, ok := attributes["rcp_command"].(string); ok && c != "" {
+		command = c
+	}
+
+	_, err = rc.rcpCommand(command, rcpTypeByte, rcpDirWrite, line, strconv.Itoa(int(state.id)))
+	if err != nil {
+		return err
+	}
+
+	// the next report is counted from zero, also by resources of any line while they read this line
+	reset := counterState{line: line, id: state.id, updated: time.Now()}
+	anyKey := counterKey{line: anyLine, name: name}
+	rc.counterLock.Lock()
+	rc.counterStates[counterKey{line: line, name: name}] = reset
+	if rc.counterStates[anyKey].line == line {
+		rc.counterStates[anyKey] = reset
+	}
+	rc.counterLock.Unlock()
+	return nil
+}
@@ -0,0 +1,182 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+func TestCounterCommandValue(t *testing.T) {
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var state counterState
+	state = state.update(counterData{ID: 3, Name: "counter", Value: 100}, start)
+	first := state
+	state = state.update(counterData{ID: 3, Name: "counter", Value: 110}, start.Add(5*time.Minute))
+	reset := state.update(counterData{ID: 3, Name: "counter", Value: 4}, start.Add(10*time.Minute))
+
+	tests := []struct {
+		name          string
+		attributes    map[string]interface{}
+		state         counterState
+		expected      interface{}
+		expectedFound bool
+		expectedError bool
+	}{
+		{"total", map[string]interface{}{"counter_name": "counter"}, state, uint32(110), true, false},
+		{"first delta", map[string]interface{}{"counter_value": "delta"}, first, nil, false, false},
+		{"delta", map[string]interface{}{"counter_value": "delta"}, state, uint32(10), true, false},
+		{"delta after reset", map[string]interface{}{"counter_value": "delta"}, reset, uint32(4), true, false},
+		{"rate per minute", map[string]interface{}{"counter_value": "rate"}, state, float32(2), true, false},
+		{"rate per 15 minutes", map[string]interface{}{"counter_value": "rate", "counter_interval": "15m"}, state, float32(30), true, false},
+		{"invalid interval", map[string]interface{}{"counter_value": "rate", "counter_interval": "often"}, state, nil, false, true},
+		{"unsupported value", map[string]interface{}{"counter_value": "average"}, state, nil, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dr := models.DeviceResource{Name: "counter", Attributes: test.attributes}
+			cv, found, err := counterCommandValue(dr, test.state)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if found != test.expectedFound {
+				t.Fatalf("Expected found %v, Received %v", test.expectedFound, found)
+			}
+			if found && cv.Value != test.expected {
+				t.Errorf("Expected %v, Received %v", test.expected, cv.Value)
+			}
+		})
+	}
+}
+
+func TestLineResources(t *testing.T) {
+	resource := func(name string, line string) lineResource {
+		dr := models.DeviceResource{Name: name, Attributes: map[string]interface{}{}}
+		if line != "" {
+			dr.Attributes["line"] = line
+		}
+		l, err := lineAttribute(dr.Attributes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return lineResource{resource: dr, line: l}
+	}
+
+	rc := &RcpClient{
+		lc:            logger.NewMockClient(),
+		alarms:        map[int][]lineResource{16: {resource("Motion", ""), resource("Motion1", "1"), resource("Motion2", "2")}},
+		counters:      map[string][]lineResource{"counter": {resource("Counter", ""), resource("Counter2", "2")}},
+		alarmStates:   make(map[string]bool),
+		counterStates: make(map[counterKey]counterState),
+	}
+
+	names := func(cvs []*sdkModels.CommandValue) []string {
+		var n []string
+		for _, cv := range cvs {
+			n = append(n, cv.DeviceResourceName)
+		}
+		return n
+	}
+
+	cvs, err := rc.commandValuesFromAlarms([]alarm{{AlarmSource: 2, AlarmType: 16, FlagState: true, FlagStateSet: true}}, models.Device{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := names(cvs); !reflect.DeepEqual(n, []string{"Motion", "Motion2"}) {
+		t.Errorf("Expected alarm readings of any line and line 2, Received %v", n)
+	}
+	if rc.getAlarmState("Motion1") || !rc.getAlarmState("Motion2") {
+		t.Errorf("Expected only the alarm state of line 2 to be set")
+	}
+
+	cvs, err = rc.commandValuesFromCounters(1, []counterData{{ID: 1, Name: "counter", Value: 5}}, models.Device{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := names(cvs); !reflect.DeepEqual(n, []string{"Counter"}) {
+		t.Errorf("Expected counter readings of any line, Received %v", n)
+	}
+	if _, ok := rc.getCounterState(2, "counter"); ok {
+		t.Errorf("Expected no counter state for line 2")
+	}
+}
+
+func TestParseCounters(t *testing.T) {
+	entry := func(id uint8, value uint32) []byte {
+		e := make([]byte, counterEntryLength)
+		e[0] = id
+		binary.BigEndian.PutUint32(e[66:], value)
+		return e
+	}
+	two := append(append([]byte{0}, entry(1, 5)...), entry(2, 7)...)
+
+	tests := []struct {
+		name     string
+		payload  []byte
+		expected []uint32
+	}{
+		{"two counters", two, []uint32{5, 7}},
+		{"empty payload", []byte{}, nil},
+		{"header only", []byte{0}, nil},
+		{"short entry", []byte{0, 1, 0, 0}, nil},
+		{"truncated last entry", two[:len(two)-1], []uint32{5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var values []uint32
+			for _, counter := range parseCounters(test.payload) {
+				values = append(values, counter.Value)
+			}
+			if !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("Expected %v, Received %v", test.expected, values)
+			}
+		})
+	}
+}
+
+func TestAnyLineCounterDelta(t *testing.T) {
+	delta := models.DeviceResource{Name: "CounterDelta", Attributes: map[string]interface{}{"counter_value": "delta"}}
+	rc := &RcpClient{
+		lc:            logger.NewMockClient(),
+		counters:      map[string][]lineResource{"counter": {{resource: delta, line: anyLine}}},
+		counterStates: make(map[counterKey]counterState),
+	}
+
+	reports := []struct {
+		line     int
+		value    uint32
+		expected []interface{}
+	}{
+		{1, 100, nil},
+		{2, 5, nil},
+		{1, 110, []interface{}{uint32(10)}},
+		{2, 8, []interface{}{uint32(3)}},
+	}
+
+	for _, report := range reports {
+		cvs, err := rc.commandValuesFromCounters(report.line, []counterData{{ID: 1, Name: "counter", Value: report.value}}, models.Device{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var values []interface{}
+		for _, cv := range cvs {
+			values = append(values, cv.Value)
+		}
+		if !reflect.DeepEqual(values, report.expected) {
+			t.Errorf("Line %d value %d: Expected %v, Received %v", report.line, report.value, report.expected, values)
+		}
+	}
+}
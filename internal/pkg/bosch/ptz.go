@@ -0,0 +1,156 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+)
+
+const (
+	// confBicomCommand tunnels a Bicom message to the camera's internal servers
+	confBicomCommand = "0x09a5"
+
+	// Bicom server and object IDs of the AUTODOME PTZ server.  Firmware which numbers them
+	// differently is supported by the bicom_server and bicom_object attributes.
+	bicomServerPTZ          = 0x0006
+	bicomObjectPanTiltSpeed = 0x0104
+	bicomObjectZoomSpeed    = 0x0105
+	bicomObjectPresetRecall = 0x0201
+	bicomObjectPresetStore  = 0x0202
+
+	bicomFlagNoReply = 0x00
+	bicomActionSet   = 0x02
+
+	// values of the bicom_ptz attribute
+	ptzMove         = "move"
+	ptzZoom         = "zoom"
+	ptzPresetRecall = "preset_recall"
+	ptzPresetStore  = "preset_store"
+)
+
+// ptzSpeed is the JSON parameter of move and zoom commands.  Speeds range from -1 to 1: positive
+// values pan right, tilt up and zoom in, and 0 stops the movement.
+type ptzSpeed struct {
+	Pan  float64
+	Tilt float64
+	Zoom float64
+}
+
+// bicomMessage is a request to a Bicom server object of the camera
+type bicomMessage struct {
+	server  uint16
+	object  uint16
+	action  uint8
+	payload []byte
+}
+
+// encode returns the message as the hex payload of a CONF_BICOM_COMMAND request
+func (m bicomMessage) encode() string {
+	b := make([]byte, 6, 6+len(m.payload))
+	b[0] = bicomFlagNoReply
+	binary.BigEndian.PutUint16(b[1:], m.server)
+	binary.BigEndian.PutUint16(b[3:], m.object)
+	b[5] = m.action
+	b = append(b, m.payload...)
+	return "0x" + hex.EncodeToString(b)
+}
+
+// bicomPTZMessage returns the Bicom message for the PTZ command given by the resource's
+// bicom_ptz attribute: move, zoom, preset_recall or preset_store
+func bicomPTZMessage(attributes map[string]interface{}, param *sdkModels.CommandValue) (bicomMessage, error) {
+	command, _ := attributes["bicom_ptz"].(string)
+	msg := bicomMessage{server: bicomServerPTZ, action: bicomActionSet}
+
+	switch command {
+	case ptzMove, ptzZoom:
+		var speed ptzSpeed
+		s, err := param.StringValue()
+		if err != nil {
+			return msg, fmt.Errorf("bicom: %s command requires a JSON String value", command)
+		}
+		err = json.Unmarshal([]byte(s), &speed)
+		if err != nil {
+			return msg, fmt.Errorf("bicom: error unmarshaling %s speed: %v", command, err.Error())
+		}
+
+		if command == ptzMove {
+			msg.object = bicomObjectPanTiltSpeed
+			msg.payload, err = bicomSpeeds(speed.Pan, speed.Tilt)
+		} else {
+			msg.object = bicomObjectZoomSpeed
+			msg.payload, err = bicomSpeeds(speed.Zoom)
+		}
+		if err != nil {
+			return msg, err
+		}
+	case ptzPresetRecall, ptzPresetStore:
+		preset, err := rcpNumber(param.Value)
+		if err != nil {
+			return msg, err
+		}
+		if preset < 1 || preset > math.MaxUint16 {
+			return msg, fmt.Errorf("bicom: preset %d out of range", preset)
+		}
+
+		msg.object = bicomObjectPresetRecall
+		if command == ptzPresetStore {
+			msg.object = bicomObjectPresetStore
+		}
+		msg.payload = []byte{byte(preset >> 8), byte(preset)}
+	default:
+		return msg, fmt.Errorf("bicom: unsupported bicom_ptz %s", command)
+	}
+
+	for attribute, id := range map[string]*uint16{"bicom_server": &msg.server, "bicom_object": &msg.object} {
+		value, ok := attributes[attribute].(string)
+		if !ok || value == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(value, 0, 16)
+		if err != nil {
+			return msg, fmt.Errorf("bicom: invalid %s %s", attribute, value)
+		}
+		*id = uint16(n)
+	}
+
+	return msg, nil
+}
+
+// bicomSpeeds encodes speeds from -1 to 1 as signed bytes
+func bicomSpeeds(speeds ...float64) ([]byte, error) {
+	b := make([]byte, len(speeds))
+	for i, speed := range speeds {
+		if speed < -1 || speed > 1 {
+			return nil, fmt.Errorf("bicom: speed %v out of range", speed)
+		}
+		b[i] = byte(int8(math.Round(speed * math.MaxInt8)))
+	}
+	return b, nil
+}
+
+// ptzCommand sends a PTZ command to the camera line given by the resource's rcp_num attribute
+func (rc *RcpClient) ptzCommand(attributes map[string]interface{}, param *sdkModels.CommandValue) error {
+	line, err := rcpNumAttribute(attributes)
+	if err != nil {
+		return fmt.Errorf("bicom: %v", err.Error())
+	}
+
+	msg, err := bicomPTZMessage(attributes, param)
+	if err != nil {
+		return err
+	}
+
+	_, err = rc.rcpCommand(confBicomCommand, rcpTypeOctet, rcpDirWrite, line, msg.encode())
+	return err
+}
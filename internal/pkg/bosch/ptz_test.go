@@ -0,0 +1,92 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"testing"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+func TestBicomPTZMessage(t *testing.T) {
+	stringValue := func(s string) *sdkModels.CommandValue {
+		return &sdkModels.CommandValue{Type: common.ValueTypeString, Value: s}
+	}
+	uint16Value := func(n uint16) *sdkModels.CommandValue {
+		return &sdkModels.CommandValue{Type: common.ValueTypeUint16, Value: n}
+	}
+
+	tests := []struct {
+		name          string
+		attributes    map[string]interface{}
+		param         *sdkModels.CommandValue
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:       "move",
+			attributes: map[string]interface{}{"bicom_ptz": "move"},
+			param:      stringValue(`{"Pan":1,"Tilt":-0.5}`),
+			expected:   "0x0000060104027fc0",
+		},
+		{
+			name:       "stop",
+			attributes: map[string]interface{}{"bicom_ptz": "move"},
+			param:      stringValue(`{}`),
+			expected:   "0x0000060104020000",
+		},
+		{
+			name:       "zoom",
+			attributes: map[string]interface{}{"bicom_ptz": "zoom"},
+			param:      stringValue(`{"Zoom":-1}`),
+			expected:   "0x00000601050281",
+		},
+		{
+			name:       "preset recall",
+			attributes: map[string]interface{}{"bicom_ptz": "preset_recall"},
+			param:      uint16Value(3),
+			expected:   "0x0000060201020003",
+		},
+		{
+			name:       "preset store with overridden object",
+			attributes: map[string]interface{}{"bicom_ptz": "preset_store", "bicom_object": "0x0299"},
+			param:      stringValue("258"),
+			expected:   "0x0000060299020102",
+		},
+		{
+			name:          "speed out of range",
+			attributes:    map[string]interface{}{"bicom_ptz": "move"},
+			param:         stringValue(`{"Pan":2}`),
+			expectedError: true,
+		},
+		{
+			name:          "preset zero",
+			attributes:    map[string]interface{}{"bicom_ptz": "preset_recall"},
+			param:         uint16Value(0),
+			expectedError: true,
+		},
+		{
+			name:          "unsupported command",
+			attributes:    map[string]interface{}{"bicom_ptz": "focus"},
+			param:         stringValue(`{}`),
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg, err := bicomPTZMessage(test.attributes, test.param)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err == nil && msg.encode() != test.expected {
+				t.Errorf("Expected %v, Received %v", test.expected, msg.encode())
+			}
+		})
+	}
+}
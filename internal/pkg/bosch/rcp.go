@@ -10,12 +10,14 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode/utf16"
 
@@ -32,6 +34,9 @@ const (
 	// confAlarmOverview and other constants are from the Bosch RCP documentation
 	confAlarmOverview    = "0x0c38"
 	confIvaCounterValues = "0x0b4a"
+	confIvaCounterReset  = "0x0b4b"
+	alarmOverviewTag     = 0x0c38
+	ivaCounterValuesTag  = 0x0b4a
 
 	alarmAddFlag      = 0x80
 	alarmDeleteFlag   = 0x40
@@ -39,6 +44,14 @@ const (
 	alarmStateSetFlag = 0x10
 
 	rcpFmtURL = "http://%s/rcp.xml?%s=%s"
+
+	rcpTypeOctet   = "P_OCTET"
+	rcpDirRead     = "READ"
+	rcpDirWrite    = "WRITE"
+	rcpDefaultLine = 1
+	// anyLine is the line of resources without a line attribute, which map alarms and counters
+	// of every line
+	anyLine = 0
 )
 
 type alarm struct {
@@ -53,6 +66,13 @@ type alarm struct {
 	AlarmName    string
 }
 
+// lineResource is a device resource mapping alarms or counters of the line given by its line
+// attribute
+type lineResource struct {
+	resource models.DeviceResource
+	line     int
+}
+
 type counterData struct {
 	ID    uint8
 	Type  uint8
@@ -72,6 +92,26 @@ type msg struct {
 	Hex     string `xml:"hex"`
 }
 
+// rcpResponse is the reply of rcp.xml to a single command
+type rcpResponse struct {
+	XMLName xml.Name  `xml:"rcp"`
+	Result  rcpResult `xml:"result"`
+}
+
+type rcpResult struct {
+	Hex string `xml:"hex"`
+	Dec string `xml:"dec"`
+	Str string `xml:"str"`
+	Err string `xml:"err"`
+}
+
+// motionRegions is the JSON form of a VCA motion area configuration read or written via RCP
+type motionRegions struct {
+	Command string
+	Line    int
+	Hex     string
+}
+
 func getXML(dc digest.Client, url string) ([]byte, error) {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
@@ -118,16 +158,35 @@ func (p *packet) utf16string(i int, n int) string {
 	return string(utf16.Decode(ints))
 }
 
+// parseAlarms returns the alarm entries of an alarm overview message.  A readout of all entries,
+// rather than a message of changes, only returns the active alarms.
 func parseAlarms(bytes []byte) (alarms []alarm) {
+	entries, readout := parseAlarmEntries(bytes)
+	for _, alarm := range entries {
+		if !readout || alarm.FlagState {
+			alarms = append(alarms, alarm)
+		}
+	}
+	return
+}
+
+// parseAlarmEntries returns every entry of an alarm overview, and whether it is a readout
+func parseAlarmEntries(bytes []byte) (alarms []alarm, readout bool) {
+	if len(bytes) < 4 {
+		return
+	}
 	packet := packet{buffer: bytes}
 
-	readout := (packet.byte(0) & 0x80) != 0
+	readout = (packet.byte(0) & 0x80) != 0
 
 	// alarm entries begin 4 bytes into payload
-	for i := 4; i < len(packet.buffer); {
+	for i := 4; i+8 <= len(packet.buffer); {
 		var alarm alarm
 		alarm.EntryID = packet.uint16(i)
 		alarm.EntryLength = packet.uint16(i + 2)
+		if alarm.EntryLength < 8 || i+int(alarm.EntryLength) > len(packet.buffer) {
+			break
+		}
 
 		flags := packet.byte(i + 4)
 		alarm.FlagAdd = (flags & alarmAddFlag) != 0
@@ -140,23 +199,28 @@ func parseAlarms(bytes []byte) (alarms []alarm) {
 		alarm.AlarmName = packet.utf16string(i+8, int(alarm.EntryLength-8))
 		i = i + int(alarm.EntryLength)
 
-		if !readout || alarm.FlagState {
-			alarms = append(alarms, alarm)
-		}
+		alarms = append(alarms, alarm)
 	}
 	return
 }
 
+// counterEntryLength is the length of a counter entry: ID, type, 64 byte name and 32 bit value
+const counterEntryLength = 70
+
 func parseCounters(bytes []byte) (counters []counterData) {
+	if len(bytes) < 1 {
+		return
+	}
 	packet := packet{buffer: bytes[1:]}
 
-	for i := 0; i < len(packet.buffer); {
+	// a truncated entry at the end is ignored
+	for i := 0; i+counterEntryLength <= len(packet.buffer); {
 		var counter counterData
 		counter.ID = packet.byte(i)
 		counter.Type = packet.byte(i + 1)
 		counter.Name = packet.utf16string(i+2, 64)
 		counter.Value = packet.uint32(i + 66)
-		i = i + 70
+		i = i + counterEntryLength
 		counters = append(counters, counter)
 	}
 	return
@@ -168,12 +232,17 @@ type RcpClient struct {
 	client    digest.Client
 	asyncChan chan<- *sdkModels.AsyncValues
 	lc        logger.LoggingClient
+	ipAddress string
+	username  string
+	password  string
 
-	alarms   map[int]models.DeviceResource
-	counters map[string]models.DeviceResource
+	alarms   map[int][]lineResource
+	counters map[string][]lineResource
 
-	alarmStates   map[int]bool
-	counterStates map[string]int
+	// alarm states are kept by resource name, counter states by line and counter name
+	alarmStates   map[string]bool
+	counterStates map[counterKey]counterState
+	counterLock   sync.Mutex
 
 	stop    chan bool
 	stopped chan bool
@@ -187,9 +256,7 @@ func NewClient(asyncCh chan<- *sdkModels.AsyncValues, lc logger.LoggingClient) c
 // CameraRelease stops the RCP listener routine
 func (rc *RcpClient) CameraRelease(force bool) {
 	close(rc.stop)
-	if !force {
-		<-rc.stopped
-	}
+	client.WaitStopped(rc.stopped, force)
 }
 
 // CameraInit initializes the RCP listener routine
@@ -198,20 +265,24 @@ func (rc *RcpClient) CameraInit(edgexDevice models.Device, edgexProfile models.D
 		rc.initializeDClient(username, password)
 	}
 
+	rc.ipAddress = ipAddress
+	rc.username = username
+	rc.password = password
+
 	if rc.alarms == nil {
-		rc.alarms = make(map[int]models.DeviceResource)
+		rc.alarms = make(map[int][]lineResource)
 	}
 
 	if rc.counters == nil {
-		rc.counters = make(map[string]models.DeviceResource)
+		rc.counters = make(map[string][]lineResource)
 	}
 
 	if rc.alarmStates == nil {
-		rc.alarmStates = make(map[int]bool)
+		rc.alarmStates = make(map[string]bool)
 	}
 
 	if rc.counterStates == nil {
-		rc.counterStates = make(map[string]int)
+		rc.counterStates = make(map[counterKey]counterState)
 	}
 
 	// a channel to tell us to stop
@@ -219,94 +290,165 @@ func (rc *RcpClient) CameraInit(edgexDevice models.Device, edgexProfile models.D
 
 	// a channel to signal that it's stopped
 	stoppedchan := make(chan bool)
-	defer close(stoppedchan)
 
 	// interrogate device profile for alarms/counters to listen for
 	deviceResources := edgexProfile.DeviceResources
 
 	for _, e := range deviceResources {
+		line, err := lineAttribute(e.Attributes)
+		if err != nil {
+			rc.lc.Errorf("Ignoring resource %s: %s", e.Name, err.Error())
+			continue
+		}
+
 		alarmType, ok := e.Attributes["alarm_type"].(string)
 		if ok {
 			val, err := strconv.Atoi(alarmType)
 			if err == nil {
-				rc.alarms[val] = e
+				rc.alarms[val] = append(rc.alarms[val], lineResource{resource: e, line: line})
 			}
 
 			continue
 		}
 
-		counterName := e.Attributes["counter_name"].(string)
-		if counterName != "" {
-			rc.counters[counterName] = e
+		counterName, ok := e.Attributes["counter_name"].(string)
+		if ok && counterName != "" {
+			rc.counters[counterName] = append(rc.counters[counterName], lineResource{resource: e, line: line})
 		}
 
 	}
 
+	rc.stop = stopchan
+	rc.stopped = stoppedchan
+
 	go func() {
-		ticks := time.Tick(time.Second * 5) //nolint:staticcheck
-
-		var maxErrors = 60
-		for maxErrors > 0 {
-			select {
-			case <-ticks:
-				err := rc.requestEvents(edgexDevice, ipAddress, stopchan)
-				if err != nil {
-					rc.lc.Error(fmt.Sprintf("Error in RCP loop: %s", err.Error()))
-					maxErrors--
-				} else {
-					maxErrors = 60
-				}
-			case <-stopchan:
-				// stop
+		defer close(stoppedchan)
+
+		// alarms are pushed over RCP+ where the firmware supports it, otherwise they are polled
+		// via HTTP for a while before trying RCP+ again
+		for {
+			err := rc.pushEvents(edgexDevice, ipAddress, stopchan)
+			if err == errRcpStopped {
+				return
+			}
+			rc.lc.Warnf("RCP+ event channel to %s unavailable, polling via HTTP: %s", ipAddress, err.Error())
+
+			if !rc.pollEvents(edgexDevice, ipAddress, stopchan, rcpPlusRetryInterval) {
 				return
 			}
 		}
 	}()
-
-	rc.stop = stopchan
-	rc.stopped = stoppedchan
 }
 
 func (rc *RcpClient) initializeDClient(username string, password string) {
 	rc.client = digest.NewDClient(&http.Client{}, username, password)
 }
 
-func (rc *RcpClient) setAlarmState(alarmType int, state bool) {
-	rc.alarmStates[alarmType] = state
+func (rc *RcpClient) setAlarmState(resourceName string, state bool) {
+	rc.alarmStates[resourceName] = state
 }
 
-func (rc *RcpClient) getAlarmState(alarmType int) bool {
-	return rc.alarmStates[alarmType]
+func (rc *RcpClient) getAlarmState(resourceName string) bool {
+	return rc.alarmStates[resourceName]
 }
 
-func (rc *RcpClient) setCounterState(counter string, count int) {
-	rc.counterStates[counter] = count
+// updateCounterState records a counter reported for a line.  Resources of any line read the state
+// of the line reported last, so their delta and rate are always those of a single line.
+func (rc *RcpClient) updateCounterState(line int, counter counterData) map[int]counterState {
+	rc.counterLock.Lock()
+	defer rc.counterLock.Unlock()
+
+	key := counterKey{line: line, name: counter.Name}
+	state := rc.counterStates[key].update(counter, time.Now())
+	state.line = line
+	rc.counterStates[key] = state
+	rc.counterStates[counterKey{line: anyLine, name: counter.Name}] = state
+
+	return map[int]counterState{line: state, anyLine: state}
 }
 
-func (rc *RcpClient) getCounterState(counter string) int {
-	return rc.counterStates[counter]
+func (rc *RcpClient) getCounterState(line int, counter string) (counterState, bool) {
+	rc.counterLock.Lock()
+	defer rc.counterLock.Unlock()
+
+	state, ok := rc.counterStates[counterKey{line: line, name: counter}]
+	return state, ok
 }
 
-// HandleReadCommand handles requests to read data from the device via the RCP api
+// HandleReadCommand handles requests to read data from the device via the RCP api: cached alarm
+// and counter states, the alarm catalogue, JPEG snapshots, or the live result of the RCP command
+// described by the resource's attributes
 func (rc *RcpClient) HandleReadCommand(req sdkModels.CommandRequest) (*sdkModels.CommandValue, error) {
 	var cv *sdkModels.CommandValue
 	var err error
 
 	if alarmType, ok := req.Attributes["alarm_type"].(string); ok {
-		alarmType, err := strconv.Atoi(alarmType)
+		_, err = strconv.Atoi(alarmType)
 		if err != nil {
 			return nil, err
 		}
-		data := rc.getAlarmState(alarmType)
+		data := rc.getAlarmState(req.DeviceResourceName)
 
 		cv, err = sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeBool, data)
 		if err != nil {
 			return nil, err
 		}
 	} else if counterType, ok := req.Attributes["counter_name"].(string); ok {
-		data := rc.getCounterState(counterType)
+		line, err := lineAttribute(req.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		state, _ := rc.getCounterState(line, counterType)
 
-		cv, err = sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeUint32, uint32(data))
+		dr := models.DeviceResource{Name: req.DeviceResourceName, Attributes: req.Attributes}
+		cv, ok, err = counterCommandValue(dr, state)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("rcp: counter %s has no previous value to compare with yet", counterType)
+		}
+	} else if _, ok := req.Attributes["alarm_catalogue"]; ok {
+		data, err := rc.getAlarmCatalogue()
+		if err != nil {
+			return nil, err
+		}
+
+		cv, err = sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeString, data)
+		if err != nil {
+			return nil, err
+		}
+	} else if _, ok := req.Attributes["snapshot"]; ok {
+		data, err := rc.getSnapshot(req.Attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		cv, err = sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeBinary, data)
+		if err != nil {
+			return nil, err
+		}
+	} else if rcpReq, ok, err := rcpRequestFromAttributes(req.Attributes, rcpDirRead); ok {
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := rc.rcpCommand(rcpReq.command, rcpReq.rcpType, rcpReq.direction, rcpReq.num, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+		if rcpReq.values != "" && req.Type == common.ValueTypeString {
+			value, err = rcpValueName(rcpReq.values, result)
+		} else {
+			value, err = rcpValue(req.Type, rcpReq.rcpType, result)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cv, err = sdkModels.NewCommandValue(req.DeviceResourceName, req.Type, value)
 		if err != nil {
 			return nil, err
 		}
@@ -317,62 +459,218 @@ func (rc *RcpClient) HandleReadCommand(req sdkModels.CommandRequest) (*sdkModels
 	return cv, nil
 }
 
-// HandleWriteCommand is unimplemented--any requests to it are unexpected
+// HandleWriteCommand resets the IVA counter given by the resource's counter_reset attribute, sends
+// the Bicom PTZ command given by its bicom_ptz attribute, or writes a value via the RCP command
+// described by its rcp_command, rcp_type and rcp_num attributes
 func (rc *RcpClient) HandleWriteCommand(req sdkModels.CommandRequest, param *sdkModels.CommandValue) error {
-	return fmt.Errorf("rcp: unrecognized write command")
+	if _, ok := req.Attributes["counter_reset"].(string); ok {
+		return rc.resetCounter(req.Attributes)
+	}
+	if _, ok := req.Attributes["bicom_ptz"].(string); ok {
+		return rc.ptzCommand(req.Attributes, param)
+	}
+
+	rcpReq, ok, err := rcpRequestFromAttributes(req.Attributes, rcpDirWrite)
+	if !ok {
+		return fmt.Errorf("rcp: unrecognized write command")
+	}
+	if err != nil {
+		return err
+	}
+
+	if rcpReq.values != "" {
+		param, err = rcpValueNumber(rcpReq.values, param)
+		if err != nil {
+			return err
+		}
+	}
+
+	payload, err := rcpPayload(rcpReq.rcpType, param)
+	if err != nil {
+		return err
+	}
+
+	_, err = rc.rcpCommand(rcpReq.command, rcpReq.rcpType, rcpReq.direction, rcpReq.num, payload)
+	return err
 }
 
+// GetMotionRegions reads the VCA motion area configuration of the RCP command given by the
+// resource's rcp_command attribute, on the line given by its rcp_num or line attribute.
+// client.ErrUnsupported is returned for resources without an rcp_command, so that the regions
+// are read via ONVIF instead.
+func (rc *RcpClient) GetMotionRegions(req sdkModels.CommandRequest) (string, error) {
+	command, ok := req.Attributes["rcp_command"].(string)
+	if !ok {
+		return "", client.ErrUnsupported
+	}
+
+	line, err := rcpNumAttribute(req.Attributes)
+	if err != nil {
+		return "", fmt.Errorf("rcp: %v", err.Error())
+	}
+
+	result, err := rc.rcpCommand(command, rcpTypeOctet, rcpDirRead, line, "")
+	if err != nil {
+		return "", err
+	}
+
+	regions, err := json.Marshal(motionRegions{Command: command, Line: line, Hex: result.Hex})
+	if err != nil {
+		return "", err
+	}
+
+	return string(regions), nil
+}
+
+// SetMotionRegions writes a VCA motion area configuration, previously read with GetMotionRegions,
+// to the RCP command given by the resource's rcp_command attribute, on the same line as
+// GetMotionRegions reads
+func (rc *RcpClient) SetMotionRegions(req sdkModels.CommandRequest, regionsJSON string) error {
+	command, ok := req.Attributes["rcp_command"].(string)
+	if !ok {
+		return client.ErrUnsupported
+	}
+
+	line, err := rcpNumAttribute(req.Attributes)
+	if err != nil {
+		return fmt.Errorf("rcp: %v", err.Error())
+	}
+
+	var regions motionRegions
+	err = json.Unmarshal([]byte(regionsJSON), &regions)
+	if err != nil {
+		return fmt.Errorf("rcp: error unmarshaling motion regions: %v", err.Error())
+	}
+
+	if regions.Line != 0 && regions.Line != line {
+		return fmt.Errorf("rcp: motion regions of line %d can't be written to line %d", regions.Line, line)
+	}
+
+	_, err = rc.rcpCommand(command, rcpTypeOctet, rcpDirWrite, line, regions.Hex)
+	return err
+}
+
+// rcpCommand issues a single RCP command via rcp.xml and returns its result
+func (rc *RcpClient) rcpCommand(command string, rcpType string, direction string, num int, payload string) (rcpResult, error) {
+	params := map[string]string{
+		"type":      rcpType,
+		"direction": direction,
+		"num":       strconv.Itoa(num),
+	}
+	if payload != "" {
+		params["payload"] = payload
+	}
+
+	url, err := getRcpURL(rc.ipAddress, "command", command, params)
+	if err != nil {
+		return rcpResult{}, err
+	}
+
+	respXML, err := getXML(rc.client, url)
+	if err != nil {
+		return rcpResult{}, fmt.Errorf("rcp: error making request: %v", err.Error())
+	}
+
+	var resp rcpResponse
+	err = xml.Unmarshal(respXML, &resp)
+	if err != nil {
+		return rcpResult{}, fmt.Errorf("rcp: error unmarshaling: %v", err.Error())
+	}
+
+	if resp.Result.Err != "" {
+		return rcpResult{}, fmt.Errorf("rcp: command %s failed with error %s", command, resp.Result.Err)
+	}
+
+	return resp.Result, nil
+}
+
+// commandValuesFromAlarms returns the readings of the resources mapping each alarm's type, and its
+// source where the resource has a line attribute
 func (rc *RcpClient) commandValuesFromAlarms(alarms []alarm, edgexDevice models.Device) ([]*sdkModels.CommandValue, error) {
 	cvs := make([]*sdkModels.CommandValue, 0)
 	var err error
 	for _, alarm := range alarms {
-		deviceResource, ok := rc.alarms[int(alarm.AlarmType)]
-
-		if !ok {
-			continue
-		}
-
 		alarmValue := alarm.FlagStateSet == alarm.FlagState
 
-		rc.setAlarmState(int(alarm.AlarmType), alarmValue)
+		for _, lr := range rc.alarms[int(alarm.AlarmType)] {
+			if lr.line != anyLine && lr.line != int(alarm.AlarmSource) {
+				continue
+			}
 
-		var cv *sdkModels.CommandValue
-		cv, err = sdkModels.NewCommandValue(deviceResource.Name, common.ValueTypeBool, alarm)
-		if err != nil {
-			rc.lc.Error("sendEvent: unable to get new bool value")
-			return []*sdkModels.CommandValue{}, fmt.Errorf("unable to create CommandValue")
+			rc.setAlarmState(lr.resource.Name, alarmValue)
+
+			var cv *sdkModels.CommandValue
+			cv, err = sdkModels.NewCommandValue(lr.resource.Name, common.ValueTypeBool, alarmValue)
+			if err != nil {
+				rc.lc.Error("sendEvent: unable to get new bool value")
+				return []*sdkModels.CommandValue{}, fmt.Errorf("unable to create CommandValue")
+			}
+			cv.Origin = time.Now().UnixNano() / int64(time.Millisecond)
+			cvs = append(cvs, cv)
 		}
-		cv.Origin = time.Now().UnixNano() / int64(time.Millisecond)
-		cvs = append(cvs, cv)
 	}
 
 	return cvs, nil
 }
 
-func (rc *RcpClient) commandValuesFromCounters(counters []counterData, edgexDevice models.Device) ([]*sdkModels.CommandValue, error) {
+// commandValuesFromCounters returns the readings of the resources mapping the counters of a line
+func (rc *RcpClient) commandValuesFromCounters(line int, counters []counterData, edgexDevice models.Device) ([]*sdkModels.CommandValue, error) {
 	cvs := make([]*sdkModels.CommandValue, 0)
 	var err error
 	for _, counter := range counters {
-		dr, ok := rc.counters[counter.Name]
-		if !ok {
-			continue
-		}
+		states := rc.updateCounterState(line, counter)
 
-		rc.setCounterState(dr.Name, int(counter.Value))
+		// each counter may be read as its total, delta and rate
+		for _, lr := range rc.counters[counter.Name] {
+			state, ok := states[lr.line]
+			if !ok {
+				continue
+			}
 
-		var cv *sdkModels.CommandValue
-		cv, err = sdkModels.NewCommandValue(dr.Name, common.ValueTypeUint32, counter.Value)
-		if err != nil {
-			rc.lc.Error("sendEvent: unable to get new uint32 value")
-			return []*sdkModels.CommandValue{}, fmt.Errorf("unable to create CommandValue")
+			var cv *sdkModels.CommandValue
+			cv, ok, err = counterCommandValue(lr.resource, state)
+			if err != nil {
+				rc.lc.Errorf("sendEvent: unable to get counter value for %s: %s", lr.resource.Name, err.Error())
+				return []*sdkModels.CommandValue{}, fmt.Errorf("unable to create CommandValue")
+			}
+			if !ok {
+				continue
+			}
+			cv.Origin = time.Now().UnixNano() / int64(time.Millisecond)
+			cvs = append(cvs, cv)
 		}
-		cv.Origin = time.Now().UnixNano() / int64(time.Millisecond)
-		cvs = append(cvs, cv)
 	}
 
 	return cvs, nil
 }
 
+// pollEvents requests events via HTTP every five seconds for the given duration.  It returns false
+// when the client is released or the camera keeps failing to respond.
+func (rc *RcpClient) pollEvents(device models.Device, ipAddress string, stopchan chan bool, duration time.Duration) bool {
+	ticker := time.NewTicker(time.Second * 5)
+	defer ticker.Stop()
+	timeout := time.After(duration)
+
+	var maxErrors = 60
+	for maxErrors > 0 {
+		select {
+		case <-ticker.C:
+			err := rc.requestEvents(device, ipAddress, stopchan)
+			if err != nil {
+				rc.lc.Error(fmt.Sprintf("Error in RCP loop: %s", err.Error()))
+				maxErrors--
+			} else {
+				maxErrors = 60
+			}
+		case <-timeout:
+			return true
+		case <-stopchan:
+			return false
+		}
+	}
+	return false
+}
+
 func (rc *RcpClient) requestEvents(device models.Device, ipAddress string, stopchan chan bool) error {
 	url, err := getRcpURL(ipAddress, "message", confAlarmOverview+"$"+confIvaCounterValues, map[string]string{"collectms": "5000"})
 	if err != nil {
@@ -392,35 +690,43 @@ func (rc *RcpClient) requestEvents(device models.Device, ipAddress string, stopc
 		return err
 	}
 
-	msgs := msgWrapper.Msgs
-
-	for _, msg := range msgs {
+	for _, msg := range msgWrapper.Msgs {
 		decoded, err := hex.DecodeString(msg.Hex[2:]) // Ignore 0x
 		if err != nil {
 			rc.lc.Error(fmt.Sprintf("error decoding: %v", err.Error()))
 			continue
 		}
 
-		var cvs []*sdkModels.CommandValue
-		switch msg.Command {
-		case confAlarmOverview:
-			alarms := parseAlarms(decoded)
-			cvs, _ = rc.commandValuesFromAlarms(alarms, device)
-		case confIvaCounterValues:
-			counters := parseCounters(decoded)
-			cvs, _ = rc.commandValuesFromCounters(counters, device)
-		default:
-			rc.lc.Warn("Unknown Command type in RCP Message")
+		line, err := strconv.Atoi(msg.Num)
+		if err != nil {
+			line = rcpDefaultLine
 		}
 
-		if len(cvs) > 0 {
-			rc.sendEvent(device, cvs)
-		}
+		rc.handleMessage(device, msg.Command, line, decoded)
 	}
 
 	return nil
 }
 
+// handleMessage sends the alarm or counter values of an RCP message of a line as async readings
+func (rc *RcpClient) handleMessage(device models.Device, command string, line int, payload []byte) {
+	var cvs []*sdkModels.CommandValue
+	switch command {
+	case confAlarmOverview:
+		alarms := parseAlarms(payload)
+		cvs, _ = rc.commandValuesFromAlarms(alarms, device)
+	case confIvaCounterValues:
+		counters := parseCounters(payload)
+		cvs, _ = rc.commandValuesFromCounters(line, counters, device)
+	default:
+		rc.lc.Warn("Unknown Command type in RCP Message")
+	}
+
+	if len(cvs) > 0 {
+		rc.sendEvent(device, cvs)
+	}
+}
+
 func getRcpURL(ip string, action string, command string, params map[string]string) (string, error) {
 	if ip == "" || action == "" || command == "" {
 		return "", fmt.Errorf("getRcpURL failed: required argument missing")
@@ -440,11 +746,12 @@ func getRcpURL(ip string, action string, command string, params map[string]strin
 	return formattedString + paramString, nil
 }
 
+// sendEvent sends the async readings unless the client is released, in which case they are dropped
 func (rc *RcpClient) sendEvent(edgexDevice models.Device, cvs []*sdkModels.CommandValue) {
 	var av sdkModels.AsyncValues
 	av.DeviceName = edgexDevice.Name
 
 	av.CommandValues = append(av.CommandValues, cvs...)
 
-	rc.asyncChan <- &av
+	client.SendAsyncValues(rc.asyncChan, rc.stop, &av)
 }
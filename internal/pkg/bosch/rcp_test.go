@@ -0,0 +1,109 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/digest"
+)
+
+func TestMotionRegionsLine(t *testing.T) {
+	stored := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		key := query.Get("command") + "/" + query.Get("num")
+		if query.Get("direction") == rcpDirWrite {
+			stored[key] = query.Get("payload")
+		}
+		fmt.Fprintf(w, "<rcp><result><hex>%s</hex></result></rcp>", stored[key])
+	}))
+	defer server.Close()
+
+	rc := &RcpClient{
+		client:    digest.NewDClient(&http.Client{}, "", ""),
+		ipAddress: strings.TrimPrefix(server.URL, "http://"),
+	}
+
+	tests := []struct {
+		name          string
+		attributes    map[string]interface{}
+		regions       motionRegions
+		expectedLine  int
+		expectedError bool
+	}{
+		{
+			name:         "default line",
+			attributes:   map[string]interface{}{"rcp_command": "0x0b0f"},
+			regions:      motionRegions{Command: "0x0b0f", Hex: "0x01"},
+			expectedLine: rcpDefaultLine,
+		},
+		{
+			name:         "line attribute",
+			attributes:   map[string]interface{}{"rcp_command": "0x0b0f", "line": "2"},
+			regions:      motionRegions{Command: "0x0b0f", Line: 2, Hex: "0x02"},
+			expectedLine: 2,
+		},
+		{
+			name:         "rcp_num attribute",
+			attributes:   map[string]interface{}{"rcp_command": "0x0b0f", "rcp_num": "3"},
+			regions:      motionRegions{Command: "0x0b0f", Hex: "0x03"},
+			expectedLine: 3,
+		},
+		{
+			name:          "regions of another line",
+			attributes:    map[string]interface{}{"rcp_command": "0x0b0f", "line": "2"},
+			regions:       motionRegions{Command: "0x0b0f", Line: 1, Hex: "0x04"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := sdkModels.CommandRequest{Attributes: test.attributes}
+			regionsJSON, err := json.Marshal(test.regions)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = rc.SetMotionRegions(req, string(regionsJSON))
+			if test.expectedError {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			read, err := rc.GetMotionRegions(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var regions motionRegions
+			err = json.Unmarshal([]byte(read), &regions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if regions.Line != test.expectedLine {
+				t.Errorf("expected line %d, got %d", test.expectedLine, regions.Line)
+			}
+			if regions.Hex != test.regions.Hex {
+				t.Errorf("expected regions %s, got %s", test.regions.Hex, regions.Hex)
+			}
+		})
+	}
+}
@@ -0,0 +1,366 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+// RCP data types, as used in the type parameter of rcp.xml
+const (
+	rcpTypeFlag    = "T_FLAG"
+	rcpTypeByte    = "T_OCTET"
+	rcpTypeWord    = "T_WORD"
+	rcpTypeDword   = "T_DWORD"
+	rcpTypeString  = "P_STRING"
+	rcpTypeUnicode = "P_UNICODE"
+)
+
+// rcpRequest is an RCP command described by the attributes of a device resource:
+// rcp_command, e.g. "0x0a8b", rcp_type, e.g. "T_FLAG", and the optional rcp_num, the line or
+// input/output number, rcp_direction, READ or WRITE for resources supporting only one of them,
+// and rcp_values, names for the values of a numeric command, e.g. "Off=0,On=1"
+type rcpRequest struct {
+	command   string
+	rcpType   string
+	num       int
+	direction string
+	values    string
+}
+
+// rcpRequestFromAttributes returns the RCP command of a device resource, or false if the resource
+// has no rcp_command attribute
+func rcpRequestFromAttributes(attributes map[string]interface{}, direction string) (rcpRequest, bool, error) {
+	command, ok := attributes["rcp_command"].(string)
+	if !ok {
+		return rcpRequest{}, false, nil
+	}
+
+	req := rcpRequest{command: command, num: rcpDefaultLine, direction: direction}
+
+	rcpType, ok := attributes["rcp_type"].(string)
+	if !ok {
+		return req, true, fmt.Errorf("rcp: command %s has no rcp_type attribute", command)
+	}
+	req.rcpType = strings.ToUpper(rcpType)
+	switch req.rcpType {
+	case rcpTypeFlag, rcpTypeByte, rcpTypeWord, rcpTypeDword, rcpTypeString, rcpTypeUnicode, rcpTypeOctet:
+	default:
+		return req, true, fmt.Errorf("rcp: command %s has unsupported rcp_type %s", command, rcpType)
+	}
+
+	num, err := rcpNumAttribute(attributes)
+	if err != nil {
+		return req, true, fmt.Errorf("rcp: command %s has %v", command, err.Error())
+	}
+	req.num = num
+
+	if values, ok := attributes["rcp_values"].(string); ok && values != "" {
+		if _, err := rcpValueNames(values); err != nil {
+			return req, true, fmt.Errorf("rcp: command %s has %v", command, err.Error())
+		}
+		switch req.rcpType {
+		case rcpTypeFlag, rcpTypeByte, rcpTypeWord, rcpTypeDword:
+		default:
+			return req, true, fmt.Errorf("rcp: command %s of type %s cannot have rcp_values", command, req.rcpType)
+		}
+		req.values = values
+	}
+
+	// rcp_direction restricts a resource to reading or writing
+	if d, ok := attributes["rcp_direction"].(string); ok && d != "" && !strings.EqualFold(d, direction) {
+		return req, true, fmt.Errorf("rcp: command %s only supports direction %s", command, strings.ToUpper(d))
+	}
+
+	return req, true, nil
+}
+
+// rcpNumAttribute returns the rcp_num attribute, the line or input/output number a command applies
+// to, which defaults to the resource's line, or 1
+func rcpNumAttribute(attributes map[string]interface{}) (int, error) {
+	if _, ok := attributes["rcp_num"]; !ok {
+		line, err := lineAttribute(attributes)
+		if err != nil || line != anyLine {
+			return line, err
+		}
+		return rcpDefaultLine, nil
+	}
+
+	num, err := intAttribute(attributes, "rcp_num")
+	if err != nil {
+		return 0, err
+	}
+	return num, nil
+}
+
+// lineAttribute returns the line attribute, the video line or encoder channel of a resource, or
+// anyLine if it has none
+func lineAttribute(attributes map[string]interface{}) (int, error) {
+	if _, ok := attributes["line"]; !ok {
+		return anyLine, nil
+	}
+
+	line, err := intAttribute(attributes, "line")
+	if err != nil {
+		return 0, err
+	}
+	if line < 1 {
+		return 0, fmt.Errorf("invalid line %d", line)
+	}
+	return line, nil
+}
+
+func intAttribute(attributes map[string]interface{}, name string) (int, error) {
+	switch n := attributes[name].(type) {
+	case string:
+		i, err := strconv.Atoi(n)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %s", name, n)
+		}
+		return i, nil
+	case float64:
+		return int(n), nil
+	case int:
+		return n, nil
+	}
+
+	return 0, fmt.Errorf("invalid %s %v", name, attributes[name])
+}
+
+// rcpValueNamed is a named value of an rcp_values attribute
+type rcpValueNamed struct {
+	name   string
+	number uint64
+}
+
+// rcpValueNames parses an rcp_values attribute, keeping the order of its names
+func rcpValueNames(values string) ([]rcpValueNamed, error) {
+	var names []rcpValueNamed
+	for _, pair := range strings.Split(values, ",") {
+		name, number, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rcp_values %s", values)
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(number), 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rcp_values %s", values)
+		}
+		names = append(names, rcpValueNamed{name: strings.TrimSpace(name), number: n})
+	}
+	return names, nil
+}
+
+// rcpValueName returns the name of a numeric result, or the number if it has no name
+func rcpValueName(values string, result rcpResult) (string, error) {
+	names, err := rcpValueNames(values)
+	if err != nil {
+		return "", err
+	}
+
+	n, err := strconv.ParseUint(rcpDecimal(result), 0, 32)
+	if err != nil {
+		return "", fmt.Errorf("rcp: invalid number result %s", rcpDecimal(result))
+	}
+	for _, named := range names {
+		if named.number == n {
+			return named.name, nil
+		}
+	}
+	return strconv.FormatUint(n, 10), nil
+}
+
+// rcpValueNumber returns the number of a value name as a String command value, which is
+// accepted by rcpPayload for numeric commands
+func rcpValueNumber(values string, param *sdkModels.CommandValue) (*sdkModels.CommandValue, error) {
+	names, err := rcpValueNames(values)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := param.StringValue()
+	if err != nil {
+		// numbers are written as they are
+		return param, nil
+	}
+	for _, named := range names {
+		if strings.EqualFold(named.name, name) {
+			return &sdkModels.CommandValue{DeviceResourceName: param.DeviceResourceName, Type: common.ValueTypeString,
+				Value: strconv.FormatUint(named.number, 10)}, nil
+		}
+	}
+	return nil, fmt.Errorf("rcp: unknown value %s, expected one of %s", name, values)
+}
+
+// rcpPayload encodes a command value as the payload parameter of an rcp.xml request of the
+// given type.  Numbers and flags are sent as decimal, strings URL encoded and P_OCTET data as hex.
+func rcpPayload(rcpType string, param *sdkModels.CommandValue) (string, error) {
+	switch rcpType {
+	case rcpTypeFlag:
+		n, err := rcpNumber(param.Value)
+		if err != nil {
+			return "", err
+		}
+		if n != 0 {
+			return "1", nil
+		}
+		return "0", nil
+	case rcpTypeByte, rcpTypeWord, rcpTypeDword:
+		n, err := rcpNumber(param.Value)
+		if err != nil {
+			return "", err
+		}
+		max := map[string]uint64{rcpTypeByte: 0xff, rcpTypeWord: 0xffff, rcpTypeDword: 0xffffffff}[rcpType]
+		if n > max {
+			return "", fmt.Errorf("rcp: value %d out of range for %s", n, rcpType)
+		}
+		return strconv.FormatUint(n, 10), nil
+	case rcpTypeString, rcpTypeUnicode:
+		s, err := param.StringValue()
+		if err != nil {
+			return "", fmt.Errorf("rcp: %s command requires a String value", rcpType)
+		}
+		return url.QueryEscape(s), nil
+	case rcpTypeOctet:
+		s, err := param.StringValue()
+		if err != nil {
+			return "", fmt.Errorf("rcp: %s command requires a hex String value", rcpType)
+		}
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+		if _, err := hex.DecodeString(s); err != nil {
+			return "", fmt.Errorf("rcp: invalid hex payload: %v", err.Error())
+		}
+		return "0x" + s, nil
+	}
+
+	return "", fmt.Errorf("rcp: unsupported rcp_type %s", rcpType)
+}
+
+// rcpNumber returns the value of an unsigned number, flag or numeric string command value
+func rcpNumber(value interface{}) (uint64, error) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case int8:
+		return rcpSigned(int64(v))
+	case int16:
+		return rcpSigned(int64(v))
+	case int32:
+		return rcpSigned(int64(v))
+	case int64:
+		return rcpSigned(v)
+	case string:
+		n, err := strconv.ParseUint(v, 0, 32)
+		if err != nil {
+			return 0, fmt.Errorf("rcp: invalid number %s", v)
+		}
+		return n, nil
+	}
+
+	return 0, fmt.Errorf("rcp: unsupported value %v", value)
+}
+
+func rcpSigned(n int64) (uint64, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("rcp: negative value %d", n)
+	}
+	return uint64(n), nil
+}
+
+// rcpValue decodes the result of an RCP read command to a value of the resource's value type.
+// Numbers and flags are taken from the decimal result, strings from the string result of
+// P_STRING and P_UNICODE commands and binary data from the hex result.
+func rcpValue(valueType string, rcpType string, result rcpResult) (interface{}, error) {
+	switch valueType {
+	case common.ValueTypeString:
+		switch rcpType {
+		case rcpTypeString, rcpTypeUnicode:
+			return result.Str, nil
+		case rcpTypeOctet:
+			return result.Hex, nil
+		}
+		return rcpDecimal(result), nil
+	case common.ValueTypeBinary:
+		data, err := hex.DecodeString(strings.TrimPrefix(strings.ReplaceAll(result.Hex, " ", ""), "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("rcp: invalid hex result: %v", err.Error())
+		}
+		return data, nil
+	case common.ValueTypeBool:
+		n, err := strconv.ParseInt(rcpDecimal(result), 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("rcp: invalid flag result %s", rcpDecimal(result))
+		}
+		return n != 0, nil
+	case common.ValueTypeFloat32, common.ValueTypeFloat64:
+		f, err := strconv.ParseFloat(rcpDecimal(result), 64)
+		if err != nil {
+			return nil, fmt.Errorf("rcp: invalid number result %s", rcpDecimal(result))
+		}
+		if valueType == common.ValueTypeFloat32 {
+			return float32(f), nil
+		}
+		return f, nil
+	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64:
+		bits := map[string]int{common.ValueTypeUint8: 8, common.ValueTypeUint16: 16, common.ValueTypeUint32: 32, common.ValueTypeUint64: 64}[valueType]
+		n, err := strconv.ParseUint(rcpDecimal(result), 0, bits)
+		if err != nil {
+			return nil, fmt.Errorf("rcp: invalid %s result %s", valueType, rcpDecimal(result))
+		}
+		switch valueType {
+		case common.ValueTypeUint8:
+			return uint8(n), nil
+		case common.ValueTypeUint16:
+			return uint16(n), nil
+		case common.ValueTypeUint32:
+			return uint32(n), nil
+		}
+		return n, nil
+	case common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64:
+		bits := map[string]int{common.ValueTypeInt8: 8, common.ValueTypeInt16: 16, common.ValueTypeInt32: 32, common.ValueTypeInt64: 64}[valueType]
+		n, err := strconv.ParseInt(rcpDecimal(result), 0, bits)
+		if err != nil {
+			return nil, fmt.Errorf("rcp: invalid %s result %s", valueType, rcpDecimal(result))
+		}
+		switch valueType {
+		case common.ValueTypeInt8:
+			return int8(n), nil
+		case common.ValueTypeInt16:
+			return int16(n), nil
+		case common.ValueTypeInt32:
+			return int32(n), nil
+		}
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("rcp: unsupported value type %s", valueType)
+}
+
+// rcpDecimal returns the numeric result of a command, which is given in hex only by some firmware
+func rcpDecimal(result rcpResult) string {
+	if result.Dec != "" {
+		return strings.TrimSpace(result.Dec)
+	}
+	return strings.TrimSpace(result.Hex)
+}
@@ -0,0 +1,222 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"reflect"
+	"testing"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+func TestRcpRequestFromAttributes(t *testing.T) {
+	tests := []struct {
+		name          string
+		attributes    map[string]interface{}
+		expected      rcpRequest
+		expectedFound bool
+		expectedError bool
+	}{
+		{
+			name:          "no rcp command",
+			attributes:    map[string]interface{}{"alarm_type": "16"},
+			expectedFound: false,
+		},
+		{
+			name:          "defaults",
+			attributes:    map[string]interface{}{"rcp_command": "0x0019", "rcp_type": "P_UNICODE"},
+			expected:      rcpRequest{command: "0x0019", rcpType: rcpTypeUnicode, num: rcpDefaultLine, direction: rcpDirWrite},
+			expectedFound: true,
+		},
+		{
+			name:          "num and direction",
+			attributes:    map[string]interface{}{"rcp_command": "0x01c1", "rcp_type": "t_flag", "rcp_num": "2", "rcp_direction": "write"},
+			expected:      rcpRequest{command: "0x01c1", rcpType: rcpTypeFlag, num: 2, direction: rcpDirWrite},
+			expectedFound: true,
+		},
+		{
+			name:          "read only",
+			attributes:    map[string]interface{}{"rcp_command": "0x01c0", "rcp_type": "T_FLAG", "rcp_direction": "READ"},
+			expectedFound: true,
+			expectedError: true,
+		},
+		{
+			name:          "missing type",
+			attributes:    map[string]interface{}{"rcp_command": "0x0019"},
+			expectedFound: true,
+			expectedError: true,
+		},
+		{
+			name:          "unsupported type",
+			attributes:    map[string]interface{}{"rcp_command": "0x0019", "rcp_type": "T_INT"},
+			expectedFound: true,
+			expectedError: true,
+		},
+		{
+			name:          "invalid num",
+			attributes:    map[string]interface{}{"rcp_command": "0x0019", "rcp_type": "T_FLAG", "rcp_num": "first"},
+			expectedFound: true,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, found, err := rcpRequestFromAttributes(test.attributes, rcpDirWrite)
+			if found != test.expectedFound {
+				t.Errorf("Expected found: %v, Result: %v", test.expectedFound, found)
+			}
+			if (err != nil) != test.expectedError {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			if !test.expectedError && req != test.expected {
+				t.Errorf("Expected: '%+v', Result: '%+v'", test.expected, req)
+			}
+		})
+	}
+}
+
+func TestRcpPayload(t *testing.T) {
+	tests := []struct {
+		name          string
+		rcpType       string
+		valueType     string
+		value         interface{}
+		expected      string
+		expectedError bool
+	}{
+		{"flag from bool", rcpTypeFlag, common.ValueTypeBool, true, "1", false},
+		{"flag from number", rcpTypeFlag, common.ValueTypeUint8, uint8(0), "0", false},
+		{"octet", rcpTypeByte, common.ValueTypeUint8, uint8(200), "200", false},
+		{"word out of range", rcpTypeWord, common.ValueTypeUint32, uint32(70000), "", true},
+		{"dword", rcpTypeDword, common.ValueTypeUint32, uint32(70000), "70000", false},
+		{"negative dword", rcpTypeDword, common.ValueTypeInt32, int32(-1), "", true},
+		{"dword from string", rcpTypeDword, common.ValueTypeString, "0x10", "16", false},
+		{"unicode", rcpTypeUnicode, common.ValueTypeString, "Gate 1&2", "Gate+1%262", false},
+		{"hex", rcpTypeOctet, common.ValueTypeString, "0a0B", "0x0a0B", false},
+		{"prefixed hex", rcpTypeOctet, common.ValueTypeString, "0x0a0b", "0x0a0b", false},
+		{"invalid hex", rcpTypeOctet, common.ValueTypeString, "0xzz", "", true},
+		{"string from number", rcpTypeString, common.ValueTypeUint8, uint8(1), "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			param, err := sdkModels.NewCommandValue("resource", test.valueType, test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			payload, err := rcpPayload(test.rcpType, param)
+			if (err != nil) != test.expectedError {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			if payload != test.expected {
+				t.Errorf("Expected: '%v', Result: '%v'", test.expected, payload)
+			}
+		})
+	}
+}
+
+func TestRcpValue(t *testing.T) {
+	tests := []struct {
+		name          string
+		valueType     string
+		rcpType       string
+		result        rcpResult
+		expected      interface{}
+		expectedError bool
+	}{
+		{"bool", common.ValueTypeBool, rcpTypeFlag, rcpResult{Hex: "0x01", Dec: "1"}, true, false},
+		{"bool from hex", common.ValueTypeBool, rcpTypeFlag, rcpResult{Hex: "0x00"}, false, false},
+		{"uint8", common.ValueTypeUint8, rcpTypeByte, rcpResult{Hex: "0xc8", Dec: "200"}, uint8(200), false},
+		{"uint8 out of range", common.ValueTypeUint8, rcpTypeWord, rcpResult{Hex: "0x0190", Dec: "400"}, nil, true},
+		{"uint32", common.ValueTypeUint32, rcpTypeDword, rcpResult{Hex: "0x00011170", Dec: "70000"}, uint32(70000), false},
+		{"int16", common.ValueTypeInt16, rcpTypeWord, rcpResult{Dec: "-12"}, int16(-12), false},
+		{"float32", common.ValueTypeFloat32, rcpTypeDword, rcpResult{Dec: "42"}, float32(42), false},
+		{"string", common.ValueTypeString, rcpTypeUnicode, rcpResult{Str: "Gate 1"}, "Gate 1", false},
+		{"string from number", common.ValueTypeString, rcpTypeDword, rcpResult{Hex: "0x2a", Dec: "42"}, "42", false},
+		{"string from octets", common.ValueTypeString, rcpTypeOctet, rcpResult{Hex: "0x0a0b"}, "0x0a0b", false},
+		{"binary", common.ValueTypeBinary, rcpTypeOctet, rcpResult{Hex: "0x0a0b"}, []byte{0x0a, 0x0b}, false},
+		{"invalid binary", common.ValueTypeBinary, rcpTypeOctet, rcpResult{Hex: "0xzz"}, nil, true},
+		{"invalid number", common.ValueTypeUint16, rcpTypeWord, rcpResult{}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := rcpValue(test.valueType, test.rcpType, test.result)
+			if (err != nil) != test.expectedError {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			if !test.expectedError && !reflect.DeepEqual(value, test.expected) {
+				t.Errorf("Expected: '%v', Result: '%v'", test.expected, value)
+			}
+		})
+	}
+}
+
+func TestRcpValueNames(t *testing.T) {
+	values := "Silent=0, Profile1=1, Profile2=2, Scheduled=3, EventTriggered=4"
+
+	readTests := []struct {
+		name     string
+		result   rcpResult
+		expected string
+	}{
+		{"named", rcpResult{Hex: "0x02", Dec: "2"}, "Profile2"},
+		{"named from hex", rcpResult{Hex: "0x04"}, "EventTriggered"},
+		{"unnamed", rcpResult{Dec: "7"}, "7"},
+	}
+	for _, test := range readTests {
+		t.Run(test.name, func(t *testing.T) {
+			name, err := rcpValueName(values, test.result)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if name != test.expected {
+				t.Errorf("Expected: '%v', Result: '%v'", test.expected, name)
+			}
+		})
+	}
+
+	writeTests := []struct {
+		name          string
+		param         *sdkModels.CommandValue
+		expected      string
+		expectedError bool
+	}{
+		{"name", &sdkModels.CommandValue{Type: common.ValueTypeString, Value: "profile1"}, "1", false},
+		{"number", &sdkModels.CommandValue{Type: common.ValueTypeUint8, Value: uint8(3)}, "3", false},
+		{"unknown name", &sdkModels.CommandValue{Type: common.ValueTypeString, Value: "Night"}, "", true},
+	}
+	for _, test := range writeTests {
+		t.Run(test.name, func(t *testing.T) {
+			param, err := rcpValueNumber(values, test.param)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			payload, err := rcpPayload(rcpTypeByte, param)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if payload != test.expected {
+				t.Errorf("Expected: '%v', Result: '%v'", test.expected, payload)
+			}
+		})
+	}
+
+	_, _, err := rcpRequestFromAttributes(map[string]interface{}{"rcp_command": "0x0019", "rcp_type": "P_UNICODE", "rcp_values": "A=1"}, rcpDirRead)
+	if err == nil {
+		t.Errorf("Expected an error for rcp_values of a string command but didn't get one")
+	}
+}
@@ -0,0 +1,258 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+const (
+	// rcpPlusPort and the other constants are from the Bosch RCP+ documentation
+	rcpPlusPort = "1756"
+
+	tpktVersion       = 0x03
+	tpktHeaderSize    = 4
+	rcpPlusHeaderSize = 16
+
+	rcpActionRequest = 0x00
+	rcpActionReply   = 0x01
+	rcpActionMessage = 0x02
+	rcpActionError   = 0x03
+
+	rcpRead  = 0x00
+	rcpWrite = 0x01
+
+	rcpDataTypeOctet = 0x0c
+
+	confRcpClientRegistration = 0xff00
+
+	rcpRegistrationNormal = 0x01
+	rcpEncryptionPlain    = 0x00
+	rcpConnectionTCP      = 0x01
+	rcpRegistrationOK     = 0x01
+
+	rcpPlusDialTimeout = 10 * time.Second
+	// rcpPlusKeepAlive is how long a connection may be idle before the alarm overview is read to
+	// check it is still alive
+	rcpPlusKeepAlive = 30 * time.Second
+	// rcpPlusRetryInterval is how long events are polled via HTTP before reconnecting
+	rcpPlusRetryInterval = 5 * time.Minute
+)
+
+var errRcpStopped = errors.New("rcp: event channel stopped")
+
+// rcpPlusPacket is a single RCP+ request, reply or message
+type rcpPlusPacket struct {
+	tag       uint16
+	dataType  uint8
+	rw        uint8
+	action    uint8
+	clientID  uint16
+	sessionID uint32
+	num       uint16
+	payload   []byte
+}
+
+// encode returns the packet with its TPKT header
+func (p rcpPlusPacket) encode() []byte {
+	length := tpktHeaderSize + rcpPlusHeaderSize + len(p.payload)
+	b := make([]byte, length)
+
+	b[0] = tpktVersion
+	binary.BigEndian.PutUint16(b[2:], uint16(length))
+
+	h := b[tpktHeaderSize:]
+	binary.BigEndian.PutUint16(h[0:], p.tag)
+	h[2] = p.dataType
+	h[3] = p.rw & 0x0f
+	h[4] = p.action & 0x0f
+	binary.BigEndian.PutUint16(h[6:], p.clientID)
+	binary.BigEndian.PutUint32(h[8:], p.sessionID)
+	binary.BigEndian.PutUint16(h[12:], p.num)
+	binary.BigEndian.PutUint16(h[14:], uint16(len(p.payload)))
+
+	copy(h[rcpPlusHeaderSize:], p.payload)
+	return b
+}
+
+// readRcpPlusPacket reads the next packet from an RCP+ connection
+func readRcpPlusPacket(r io.Reader) (rcpPlusPacket, error) {
+	var tpkt [tpktHeaderSize]byte
+	if _, err := io.ReadFull(r, tpkt[:]); err != nil {
+		return rcpPlusPacket{}, err
+	}
+	if tpkt[0] != tpktVersion {
+		return rcpPlusPacket{}, fmt.Errorf("rcp: invalid TPKT version %d", tpkt[0])
+	}
+
+	length := int(binary.BigEndian.Uint16(tpkt[2:]))
+	if length < tpktHeaderSize+rcpPlusHeaderSize {
+		return rcpPlusPacket{}, fmt.Errorf("rcp: invalid packet length %d", length)
+	}
+
+	b := make([]byte, length-tpktHeaderSize)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return rcpPlusPacket{}, err
+	}
+
+	p := rcpPlusPacket{
+		tag:       binary.BigEndian.Uint16(b[0:]),
+		dataType:  b[2],
+		rw:        b[3] & 0x0f,
+		action:    b[4] & 0x0f,
+		clientID:  binary.BigEndian.Uint16(b[6:]),
+		sessionID: binary.BigEndian.Uint32(b[8:]),
+		num:       binary.BigEndian.Uint16(b[12:]),
+	}
+
+	payloadLength := int(binary.BigEndian.Uint16(b[14:]))
+	if payloadLength > len(b)-rcpPlusHeaderSize {
+		return rcpPlusPacket{}, fmt.Errorf("rcp: invalid payload length %d", payloadLength)
+	}
+	p.payload = b[rcpPlusHeaderSize : rcpPlusHeaderSize+payloadLength]
+
+	return p, nil
+}
+
+// registrationPayload is the CONF_RCP_CLIENT_REGISTRATION payload registering for the messages
+// of the given tags.  Credentials are sent in plain text, as for rcp.xml.
+func registrationPayload(username string, password string, tags []uint16) []byte {
+	auth := "+" + username + ":" + password + "+"
+
+	b := make([]byte, 8, 8+len(auth)+2+2*len(tags))
+	b[0] = rcpRegistrationNormal
+	b[4] = rcpEncryptionPlain
+	b[5] = rcpConnectionTCP
+	binary.BigEndian.PutUint16(b[6:], uint16(len(auth)))
+	b = append(b, auth...)
+
+	b = append(b, 0, 0)
+	binary.BigEndian.PutUint16(b[len(b)-2:], uint16(len(tags)))
+	for _, tag := range tags {
+		b = append(b, byte(tag>>8), byte(tag))
+	}
+	return b
+}
+
+// pushEvents registers for alarm and counter messages over a persistent RCP+ connection and
+// handles them as the camera pushes them, until stopped or the connection fails
+func (rc *RcpClient) pushEvents(device models.Device, ipAddress string, stopchan chan bool) error {
+	host, _, err := net.SplitHostPort(ipAddress)
+	if err != nil {
+		host = ipAddress
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, rcpPlusPort), rcpPlusDialTimeout)
+	if err != nil {
+		return err
+	}
+
+	return rc.handleRcpPlus(conn, device, stopchan)
+}
+
+// handleRcpPlus registers for messages on an RCP+ connection and handles them until the connection
+// fails or the client is released, which closes the connection
+func (rc *RcpClient) handleRcpPlus(conn net.Conn, device models.Device, stopchan chan bool) error {
+	// closing the connection ends the blocking read below when the client is released
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		select {
+		case <-stopchan:
+		case <-done:
+		}
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+
+	clientID, err := rc.registerRcpPlus(conn, reader)
+	if err != nil {
+		return rc.rcpPlusError(err, stopchan)
+	}
+	rc.lc.Infof("RCP+ event channel to %s registered", conn.RemoteAddr().String())
+
+	keepAlive := rcpPlusPacket{tag: alarmOverviewTag, dataType: rcpDataTypeOctet, rw: rcpRead, action: rcpActionRequest, clientID: clientID, num: rcpDefaultLine}
+	awaitingReply := false
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(rcpPlusKeepAlive))
+		p, err := readRcpPlusPacket(reader)
+		if err != nil {
+			// an idle connection is probed with a keep-alive, which must be answered in time
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() && !awaitingReply {
+				if _, err := conn.Write(keepAlive.encode()); err != nil {
+					return rc.rcpPlusError(err, stopchan)
+				}
+				awaitingReply = true
+				continue
+			}
+			return rc.rcpPlusError(err, stopchan)
+		}
+		awaitingReply = false
+
+		// replies only answer the keep-alive, and their readout of the active alarms would repeat
+		// the readings of alarms already reported
+		switch p.action {
+		case rcpActionMessage:
+			rc.handleMessage(device, fmt.Sprintf("0x%04x", p.tag), int(p.num), p.payload)
+		case rcpActionError:
+			rc.lc.Warnf("RCP+ error reply for command 0x%04x", p.tag)
+		}
+	}
+}
+
+func (rc *RcpClient) registerRcpPlus(conn net.Conn, reader *bufio.Reader) (uint16, error) {
+	registration := rcpPlusPacket{
+		tag:      confRcpClientRegistration,
+		dataType: rcpDataTypeOctet,
+		rw:       rcpWrite,
+		action:   rcpActionRequest,
+		payload:  registrationPayload(rc.username, rc.password, []uint16{alarmOverviewTag, ivaCounterValuesTag}),
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(rcpPlusDialTimeout))
+	if _, err := conn.Write(registration.encode()); err != nil {
+		return 0, err
+	}
+
+	for {
+		p, err := readRcpPlusPacket(reader)
+		if err != nil {
+			return 0, err
+		}
+		if p.tag != confRcpClientRegistration {
+			continue
+		}
+		if p.action == rcpActionError || len(p.payload) < 4 {
+			return 0, errors.New("rcp: RCP+ registration not supported")
+		}
+		if p.payload[0] != rcpRegistrationOK {
+			return 0, errors.New("rcp: RCP+ registration refused")
+		}
+
+		_ = conn.SetDeadline(time.Time{})
+		return binary.BigEndian.Uint16(p.payload[2:]), nil
+	}
+}
+
+// rcpPlusError returns errRcpStopped for errors caused by the client being released
+func (rc *RcpClient) rcpPlusError(err error, stopchan chan bool) error {
+	select {
+	case <-stopchan:
+		return errRcpStopped
+	default:
+		return err
+	}
+}
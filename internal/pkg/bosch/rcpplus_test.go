@@ -0,0 +1,134 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+func TestRcpPlusPacket(t *testing.T) {
+	p := rcpPlusPacket{
+		tag:       alarmOverviewTag,
+		dataType:  rcpDataTypeOctet,
+		rw:        rcpWrite,
+		action:    rcpActionMessage,
+		clientID:  0x1234,
+		sessionID: 0xdeadbeef,
+		num:       2,
+		payload:   []byte{1, 2, 3},
+	}
+
+	encoded := p.encode()
+	if len(encoded) != tpktHeaderSize+rcpPlusHeaderSize+3 || encoded[0] != tpktVersion {
+		t.Fatalf("Unexpected encoding: %x", encoded)
+	}
+
+	decoded, err := readRcpPlusPacket(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, p) {
+		t.Errorf("Expected: '%+v', Result: '%+v'", p, decoded)
+	}
+
+	_, err = readRcpPlusPacket(bytes.NewReader(encoded[:10]))
+	if err == nil {
+		t.Errorf("Expected an error for a truncated packet")
+	}
+}
+
+func TestHandleRcpPlus(t *testing.T) {
+	asyncCh := make(chan *sdkModels.AsyncValues, 1)
+	rc := &RcpClient{
+		asyncChan:   asyncCh,
+		lc:          logger.NewMockClient(),
+		username:    "service",
+		password:    "secret",
+		alarms:      map[int][]lineResource{16: {{resource: models.DeviceResource{Name: "MotionDetected"}}}},
+		alarmStates: make(map[string]bool),
+	}
+
+	conn, camera := net.Pipe()
+	stop := make(chan bool)
+	result := make(chan error)
+	go func() {
+		result <- rc.handleRcpPlus(conn, models.Device{Name: "Camera001"}, stop)
+	}()
+
+	registration, err := readRcpPlusPacket(camera)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registration.tag != confRcpClientRegistration || !bytes.Contains(registration.payload, []byte("+service:secret+")) {
+		t.Fatalf("Unexpected registration: %+v", registration)
+	}
+	reply := rcpPlusPacket{tag: confRcpClientRegistration, dataType: rcpDataTypeOctet, action: rcpActionReply, payload: []byte{rcpRegistrationOK, 0, 0, 7}}
+	if _, err := camera.Write(reply.encode()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the reply to a keep-alive reads out the active alarms, which have already been reported
+	keepAliveReply := rcpPlusPacket{tag: alarmOverviewTag, dataType: rcpDataTypeOctet, action: rcpActionReply, payload: alarmPayload(16, "Motion")}
+	if _, err := camera.Write(keepAliveReply.encode()); err != nil {
+		t.Fatal(err)
+	}
+
+	message := rcpPlusPacket{tag: alarmOverviewTag, dataType: rcpDataTypeOctet, action: rcpActionMessage, payload: alarmPayload(16, "Motion")}
+	if _, err := camera.Write(message.encode()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case av := <-asyncCh:
+		if av.DeviceName != "Camera001" || len(av.CommandValues) != 1 || av.CommandValues[0].DeviceResourceName != "MotionDetected" {
+			t.Errorf("Unexpected async values: %+v", av)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("No async values for the pushed alarm")
+	}
+
+	select {
+	case av := <-asyncCh:
+		t.Errorf("Unexpected async values for the keep-alive reply: %+v", av)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(stop)
+	select {
+	case err := <-result:
+		if err != errRcpStopped {
+			t.Errorf("Expected errRcpStopped, Result: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("RCP+ connection not released")
+	}
+}
+
+// alarmPayload is an alarm overview message with a single alarm which is set
+func alarmPayload(alarmType uint8, name string) []byte {
+	var nameBytes bytes.Buffer
+	_ = binary.Write(&nameBytes, binary.BigEndian, utf16.Encode([]rune(name)))
+
+	entry := make([]byte, 8)
+	binary.BigEndian.PutUint16(entry[0:], 1)
+	binary.BigEndian.PutUint16(entry[2:], uint16(8+nameBytes.Len()))
+	entry[4] = alarmStateFlag | alarmStateSetFlag
+	entry[6] = 1
+	entry[7] = alarmType
+
+	return append(append(make([]byte, 4), entry...), nameBytes.Bytes()...)
+}
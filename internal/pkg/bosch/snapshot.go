@@ -0,0 +1,104 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const snapshotFmtURL = "http://%s/snap.jpg"
+
+// snapshotResolution matches the JpegSize names, e.g. "XL", or a width in pixels, e.g. "1280"
+var snapshotResolution = regexp.MustCompile(`^(?i:S|M|L|XL|[0-9]{2,4})$`)
+
+// snapshotURL returns the snap.jpg URL of the JPEG snapshot described by the resource's
+// attributes: snapshot_line, the camera line, which defaults to the line attribute,
+// snapshot_stream, the encoder stream, snapshot_resolution, a JpegSize such as "M" or "1280",
+// and snapshot_quality, from 1 to 100.  The camera's defaults are used for attributes which
+// aren't given.
+func snapshotURL(ipAddress string, attributes map[string]interface{}) (string, error) {
+	query := url.Values{}
+
+	line, err := lineAttribute(attributes)
+	if err != nil {
+		return "", fmt.Errorf("snapshot: %v", err.Error())
+	}
+	if line != anyLine {
+		query.Set("JpegCam", strconv.Itoa(line))
+	}
+
+	for attribute, param := range map[string]string{"snapshot_line": "JpegCam", "snapshot_stream": "JpegStream"} {
+		value, ok := attributes[attribute].(string)
+		if !ok || value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return "", fmt.Errorf("snapshot: invalid %s %s", attribute, value)
+		}
+		query.Set(param, value)
+	}
+
+	if resolution, ok := attributes["snapshot_resolution"].(string); ok && resolution != "" {
+		if !snapshotResolution.MatchString(resolution) {
+			return "", fmt.Errorf("snapshot: invalid snapshot_resolution %s", resolution)
+		}
+		query.Set("JpegSize", strings.ToUpper(resolution))
+	}
+
+	if quality, ok := attributes["snapshot_quality"].(string); ok && quality != "" {
+		n, err := strconv.Atoi(quality)
+		if err != nil || n < 1 || n > 100 {
+			return "", fmt.Errorf("snapshot: invalid snapshot_quality %s", quality)
+		}
+		query.Set("JpegQuality", quality)
+	}
+
+	snapURL := fmt.Sprintf(snapshotFmtURL, ipAddress)
+	if len(query) > 0 {
+		snapURL += "?" + query.Encode()
+	}
+	return snapURL, nil
+}
+
+// getSnapshot returns a JPEG snapshot from the camera's snap.jpg CGI, without the ONVIF
+// GetSnapshotUri round trip
+func (rc *RcpClient) getSnapshot(attributes map[string]interface{}) ([]byte, error) {
+	url, err := snapshotURL(rc.ipAddress, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: new request GET Error: %v", err.Error())
+	}
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: GET Error: %v", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("snapshot: status Error: %v", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/jpeg") {
+		return nil, fmt.Errorf("snapshot: unexpected content type %s", contentType)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: read Error: %v", err.Error())
+	}
+	return data, nil
+}
@@ -0,0 +1,69 @@
+// -*- mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2019
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bosch
+
+import (
+	"testing"
+)
+
+func TestSnapshotURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		attributes    map[string]interface{}
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:       "defaults",
+			attributes: map[string]interface{}{"snapshot": "jpeg"},
+			expected:   "http://192.168.1.10/snap.jpg",
+		},
+		{
+			name: "all attributes",
+			attributes: map[string]interface{}{"snapshot": "jpeg", "snapshot_line": "2", "snapshot_stream": "1",
+				"snapshot_resolution": "xl", "snapshot_quality": "80"},
+			expected: "http://192.168.1.10/snap.jpg?JpegCam=2&JpegQuality=80&JpegSize=XL&JpegStream=1",
+		},
+		{
+			name:       "line",
+			attributes: map[string]interface{}{"snapshot": "jpeg", "line": "3"},
+			expected:   "http://192.168.1.10/snap.jpg?JpegCam=3",
+		},
+		{
+			name:       "width",
+			attributes: map[string]interface{}{"snapshot": "jpeg", "snapshot_resolution": "1280"},
+			expected:   "http://192.168.1.10/snap.jpg?JpegSize=1280",
+		},
+		{
+			name:          "invalid line",
+			attributes:    map[string]interface{}{"snapshot": "jpeg", "snapshot_line": "0"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid resolution",
+			attributes:    map[string]interface{}{"snapshot": "jpeg", "snapshot_resolution": "huge"},
+			expectedError: true,
+		},
+		{
+			name:          "quality out of range",
+			attributes:    map[string]interface{}{"snapshot": "jpeg", "snapshot_quality": "101"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url, err := snapshotURL("192.168.1.10", test.attributes)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if url != test.expected {
+				t.Errorf("Expected '%v', Received '%v'", test.expected, url)
+			}
+		})
+	}
+}
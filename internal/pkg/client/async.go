@@ -0,0 +1,41 @@
+package client
+
+import (
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+)
+
+// ForceStopTimeout bounds the wait for a listener goroutine when the driver is stopped by force
+const ForceStopTimeout = time.Second
+
+// SendAsyncValues sends async values unless the listener is stopped, in which case the driver may
+// already have closed the async channel.  Returns false when stopped.
+func SendAsyncValues(asyncCh chan<- *sdkModels.AsyncValues, stop <-chan bool, av *sdkModels.AsyncValues) bool {
+	select {
+	case <-stop:
+		return false
+	default:
+	}
+
+	select {
+	case <-stop:
+		return false
+	case asyncCh <- av:
+		return true
+	}
+}
+
+// WaitStopped waits for a listener goroutine to end.  A forced wait gives up after
+// ForceStopTimeout; the goroutine then ends on its next send, see SendAsyncValues.
+func WaitStopped(stopped <-chan bool, force bool) {
+	if !force {
+		<-stopped
+		return
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(ForceStopTimeout):
+	}
+}
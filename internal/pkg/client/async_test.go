@@ -0,0 +1,42 @@
+package client
+
+import (
+	"testing"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+)
+
+func TestSendAsyncValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		stopped  bool
+		closed   bool
+		buffered bool
+		expected bool
+	}{
+		{"running", false, false, true, true},
+		{"stopped with a blocked channel", true, false, false, false},
+		{"stopped with a closed channel", true, true, true, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			asyncCh := make(chan *sdkModels.AsyncValues)
+			if test.buffered {
+				asyncCh = make(chan *sdkModels.AsyncValues, 1)
+			}
+			stop := make(chan bool)
+			if test.stopped {
+				close(stop)
+			}
+			if test.closed {
+				close(asyncCh)
+			}
+
+			sent := SendAsyncValues(asyncCh, stop, &sdkModels.AsyncValues{DeviceName: "Camera001"})
+			if sent != test.expected {
+				t.Errorf("Expected: '%v', Result: '%v'", test.expected, sent)
+			}
+		})
+	}
+}
@@ -1,10 +1,17 @@
 package client
 
 import (
+	"errors"
+
 	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
 )
 
+// ErrUnsupported is returned by the optional client interfaces when the camera or the requested
+// device resource is not configured for the manufacturer-specific API, in which case the caller
+// should fall back to ONVIF
+var ErrUnsupported = errors.New("unsupported by camera client")
+
 // Client is an interface that can be implemented to allow cameras to pass HandleReadCommand
 // and HandleWriteCommand requests to device- or manufacturer-specific handlers.  CameraInit
 // and CameraRelease allow cameras to spin up long-running goroutines to manage the camera
@@ -14,3 +21,11 @@ type Client interface {
 	HandleWriteCommand(req sdkModels.CommandRequest, param *sdkModels.CommandValue) error
 	CameraRelease(force bool)
 }
+
+// MotionRegionClient can be implemented by clients for cameras that expose their motion
+// detection regions through a proprietary API.  Regions are exchanged as a JSON string whose
+// layout is specific to the manufacturer.
+type MotionRegionClient interface {
+	GetMotionRegions(req sdkModels.CommandRequest) (string, error)
+	SetMotionRegions(req sdkModels.CommandRequest, regions string) error
+}
@@ -0,0 +1,12 @@
+package client
+
+import "regexp"
+
+// resolution matches image resolutions given as width x height in pixels, e.g. "640x480"
+var resolution = regexp.MustCompile(`^[0-9]{2,5}x[0-9]{2,5}$`)
+
+// ValidResolution reports whether a resolution setting or attribute, which is passed on to the
+// camera's API, is of the form "640x480"
+func ValidResolution(value string) bool {
+	return resolution.MatchString(value)
+}
@@ -0,0 +1,24 @@
+package client
+
+import "testing"
+
+func TestValidResolution(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{"640x480", true},
+		{"1920x1080", true},
+		{"160x90", true},
+		{"640X480", false},
+		{"640x", false},
+		{"640x480&camera=2", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if valid := ValidResolution(test.value); valid != test.expected {
+			t.Errorf("%s: Expected %v, Received %v", test.value, test.expected, valid)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 )
 
 // Client is an interface with a single Do() method, allowing functions to accept
@@ -17,11 +18,14 @@ type Client interface {
 }
 
 // DClient represents an HTTP client used for making requests authenticated
-// with http digest authentication.
+// with http digest authentication.  It is safe for concurrent use.
 type DClient struct {
-	client     *http.Client
-	username   string
-	password   string
+	client   *http.Client
+	username string
+	password string
+
+	// lock guards the nonce state, which is shared by concurrent requests
+	lock       sync.Mutex
 	snonce     string
 	realm      string
 	qop        string
@@ -43,7 +47,11 @@ func (dc *DClient) Do(req *http.Request) (*http.Response, error) {
 }
 
 func (dc *DClient) doDigestAuth(req *http.Request) (*http.Response, error) {
-	if dc.snonce != "" {
+	dc.lock.Lock()
+	hasNonce := dc.snonce != ""
+	dc.lock.Unlock()
+
+	if hasNonce {
 		digestAuth, err := dc.getDigestAuth(req.Method, req.URL.String())
 		if err != nil {
 			return &http.Response{}, err
@@ -66,12 +74,23 @@ func (dc *DClient) doDigestAuth(req *http.Request) (*http.Response, error) {
 
 	dc.getDigestParts(resp)
 
-	authedReq, err := http.NewRequest(req.Method, req.URL.String(), req.Body)
+	// the body of the first attempt has been consumed, so requests with a body are resent with a copy
+	body := req.Body
+	if req.GetBody != nil {
+		body, err = req.GetBody()
+		if err != nil {
+			return &http.Response{}, fmt.Errorf("GetBody: %v", err.Error())
+		}
+	}
+
+	// the retry keeps the context of the request, so its deadline or cancellation still applies
+	authedReq, err := http.NewRequestWithContext(req.Context(), req.Method, req.URL.String(), body)
 	if err != nil {
 		return &http.Response{}, fmt.Errorf("http.NewRequest: %v", err.Error())
 	}
 
 	authedReq.Header = req.Header
+	authedReq.ContentLength = req.ContentLength
 	digestAuth, err := dc.getDigestAuth(authedReq.Method, authedReq.URL.String())
 	if err != nil {
 		return &http.Response{}, err
@@ -102,6 +121,8 @@ func (dc *DClient) getDigestParts(resp *http.Response) {
 			}
 		}
 	}
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
 	dc.snonce = result["nonce"]
 	dc.realm = result["realm"]
 	dc.qop = result["qop"]
@@ -123,13 +144,16 @@ func getCnonce() (string, error) {
 }
 
 func (dc *DClient) getDigestAuth(method string, uri string) (string, error) {
-	ha1 := getMD5(dc.username + ":" + dc.realm + ":" + dc.password)
-	ha2 := getMD5(method + ":" + uri)
 	cnonce, err := getCnonce()
 	if err != nil {
 		return "nil", err
 	}
+
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
 	dc.nonceCount++
+	ha1 := getMD5(dc.username + ":" + dc.realm + ":" + dc.password)
+	ha2 := getMD5(method + ":" + uri)
 	response := getMD5(fmt.Sprintf("%s:%s:%v:%s:%s:%s", ha1, dc.snonce, dc.nonceCount, cnonce, dc.qop, ha2))
 	authorization := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", cnonce="%s", nc="%v", qop="%s", response="%s"`,
 		dc.username, dc.realm, dc.snonce, uri, cnonce, dc.nonceCount, dc.qop, response)
@@ -0,0 +1,31 @@
+// Package eventtopic matches the topics of ONVIF-style event notifications, as sent by ONVIF cameras
+// and by the Axis event stream.
+package eventtopic
+
+import "strings"
+
+// Matches compares two event topics ignoring their namespace prefixes, which cameras are free to
+// choose, e.g. "tns1:RuleEngine/CellMotionDetector/Motion" and "tnsaxis:"
+func Matches(topic string, expected string) bool {
+	a, b := localNames(topic), localNames(expected)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// localNames returns the segments of a topic without their namespace prefixes
+func localNames(topic string) []string {
+	segments := strings.Split(strings.TrimSpace(topic), "/")
+	for i, segment := range segments {
+		if _, local, found := strings.Cut(segment, ":"); found {
+			segments[i] = local
+		}
+	}
+	return segments
+}
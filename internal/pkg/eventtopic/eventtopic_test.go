@@ -0,0 +1,29 @@
+package eventtopic
+
+import "testing"
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		topic     string
+		expected  string
+		expResult bool
+	}{
+		{"same topic", "tns1:VideoAnalytics/Radiometry/BoxTemperatureReading", "tns1:VideoAnalytics/Radiometry/BoxTemperatureReading", true},
+		{"different prefixes", "ns0:VideoAnalytics/ns1:Radiometry/ns1:BoxTemperatureReading", "tns1:VideoAnalytics/Radiometry/BoxTemperatureReading", true},
+		{"vendor prefixes", "tns1:Device/tnsaxis:IO/Port", "onvif:Device/axis:IO/Port", true},
+		{"parent topic", "tns1:VideoAnalytics/Radiometry", "tns1:VideoAnalytics/Radiometry/BoxTemperatureReading", false},
+		{"child topic", "tns1:VideoSource/MotionAlarm", "tns1:VideoSource", false},
+		{"different topic", "tns1:VideoAnalytics/Radiometry/SpotTemperatureReading", "tns1:VideoAnalytics/Radiometry/BoxTemperatureReading", false},
+		{"different last segment", "tns1:Device/tnsaxis:IO/VirtualPort", "tns1:Device/tnsaxis:IO/Port", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := Matches(test.topic, test.expected)
+			if res != test.expResult {
+				t.Errorf("Expected: '%v', Result: '%v'", test.expResult, res)
+			}
+		})
+	}
+}
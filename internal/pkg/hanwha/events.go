@@ -0,0 +1,160 @@
+package hanwha
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+const (
+	eventStatusFmtURL = "%s/eventstatus.cgi?%s"
+
+	// idleWait is the wait between polls which report no changes, for firmware replying
+	// immediately rather than holding the request until an event changes
+	idleWait = time.Second
+)
+
+// eventResource is a device resource whose readings are the state of a SUNAPI event.  It is
+// configured by the resource attributes sunapi_event, the name of the event below its channel,
+// e.g. "MotionDetection", "Tampering", "AudioDetection" or "VideoAnalytics.Passing", and
+// sunapi_channel, the video channel, 0 by default.
+type eventResource struct {
+	resource models.DeviceResource
+	key      string
+}
+
+func eventResourcesFromProfile(profile models.DeviceProfile, lc logger.LoggingClient) []eventResource {
+	var resources []eventResource
+	for _, dr := range profile.DeviceResources {
+		event, ok := dr.Attributes["sunapi_event"].(string)
+		if !ok || event == "" {
+			continue
+		}
+		channel, err := channelAttribute(dr.Attributes)
+		if err != nil {
+			lc.Errorf("Ignoring resource %s: %s", dr.Name, err.Error())
+			continue
+		}
+
+		resources = append(resources, eventResource{resource: dr, key: fmt.Sprintf("Channel.%d.%s", channel, event)})
+	}
+	return resources
+}
+
+func channelAttribute(attributes map[string]interface{}) (int, error) {
+	value, ok := attributes["sunapi_channel"].(string)
+	if !ok || value == "" {
+		return 0, nil
+	}
+	channel, err := strconv.Atoi(value)
+	if err != nil || channel < 0 {
+		return 0, fmt.Errorf("sunapi: invalid sunapi_channel %s", value)
+	}
+	return channel, nil
+}
+
+// monitorEvents polls eventstatus.cgi with the monitordiff action, which holds each request until
+// an event changes and replies with the changed events, and sends the states of the mapped
+// events as async readings until the client is released
+func (c *SunapiClient) monitorEvents(edgexDevice models.Device) error {
+	// cancelling the context ends the pending request when the client is released
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.stop:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	query := url.Values{"msubmenu": {"eventstatus"}, "action": {"monitordiff"}}
+	monitorURL := fmt.Sprintf(eventStatusFmtURL, c.baseURL, query.Encode())
+
+	states := make(map[string]bool)
+	for {
+		resp, err := c.getWithContext(ctx, monitorURL)
+		if err != nil {
+			return c.monitorError(err)
+		}
+		body, err := readBody(resp)
+		if err != nil {
+			return c.monitorError(err)
+		}
+		if err = sunapiError(body); err != nil {
+			return err
+		}
+
+		cvs := c.eventCommandValues(parseEventStatus(string(body)), states)
+		if len(cvs) > 0 {
+			if !c.sendEvent(edgexDevice, cvs) {
+				return errCancelled
+			}
+			continue
+		}
+
+		select {
+		case <-c.stop:
+			return errCancelled
+		case <-time.After(idleWait):
+		}
+	}
+}
+
+// monitorError returns errCancelled for errors caused by the client being released
+func (c *SunapiClient) monitorError(err error) error {
+	select {
+	case <-c.stop:
+		return errCancelled
+	default:
+		return fmt.Errorf("monitorEvents: %v", err.Error())
+	}
+}
+
+// eventCommandValues returns the readings of the mapped events whose states changed
+func (c *SunapiClient) eventCommandValues(status map[string]bool, states map[string]bool) []*sdkModels.CommandValue {
+	var cvs []*sdkModels.CommandValue
+	for _, er := range c.events {
+		state, ok := status[er.key]
+		if !ok {
+			continue
+		}
+		if last, known := states[er.resource.Name]; known && last == state {
+			continue
+		}
+		states[er.resource.Name] = state
+
+		cv, err := newBoolCommandValue(er.resource.Name, state)
+		if err != nil {
+			c.lc.Errorf("Unable to create reading for resource '%s': %s", er.resource.Name, err.Error())
+			continue
+		}
+		cvs = append(cvs, cv)
+	}
+	return cvs
+}
+
+// parseEventStatus parses the "Channel.0.MotionDetection=True" lines of an eventstatus.cgi reply.
+// Items which aren't boolean, e.g. counts, are ignored.
+func parseEventStatus(body string) map[string]bool {
+	status := make(map[string]bool)
+	for _, line := range strings.Split(body, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found {
+			continue
+		}
+		state, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		status[strings.TrimSpace(key)] = state
+	}
+	return status
+}
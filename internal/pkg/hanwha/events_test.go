@@ -0,0 +1,140 @@
+package hanwha
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+func TestParseEventStatus(t *testing.T) {
+	body := "Channel.0.MotionDetection=True\r\nChannel.0.Tampering=False\r\nChannel.1.VideoAnalytics.Passing=True\r\nChannel.0.ObjectCount=12\r\n\r\n"
+
+	status := parseEventStatus(body)
+	expected := map[string]bool{
+		"Channel.0.MotionDetection":        true,
+		"Channel.0.Tampering":              false,
+		"Channel.1.VideoAnalytics.Passing": true,
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("Expected %v, Received %v", expected, status)
+	}
+}
+
+func TestEventResourcesFromProfile(t *testing.T) {
+	profile := models.DeviceProfile{DeviceResources: []models.DeviceResource{
+		{Name: "Motion", Attributes: map[string]interface{}{"sunapi_event": "MotionDetection"}},
+		{Name: "Passing", Attributes: map[string]interface{}{"sunapi_event": "VideoAnalytics.Passing", "sunapi_channel": "2"}},
+		{Name: "Invalid", Attributes: map[string]interface{}{"sunapi_event": "Tampering", "sunapi_channel": "A"}},
+		{Name: "Snapshot", Attributes: map[string]interface{}{"sunapi_snapshot": "jpeg"}},
+	}}
+
+	resources := eventResourcesFromProfile(profile, logger.NewMockClient())
+	var keys []string
+	for _, er := range resources {
+		keys = append(keys, er.resource.Name+" "+er.key)
+	}
+	expected := []string{"Motion Channel.0.MotionDetection", "Passing Channel.2.VideoAnalytics.Passing"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected %v, Received %v", expected, keys)
+	}
+}
+
+func TestMonitorEvents(t *testing.T) {
+	replies := []string{
+		"Channel.0.MotionDetection=False\r\nChannel.0.Tampering=False\r\nChannel.0.AudioDetection=False\r\n",
+		"Channel.0.MotionDetection=True\r\n",
+	}
+	var lock sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stw-cgi/eventstatus.cgi" || r.URL.Query().Get("action") != "monitordiff" {
+			http.NotFound(w, r)
+			return
+		}
+
+		lock.Lock()
+		if len(replies) == 0 {
+			lock.Unlock()
+			// hold the request like the camera does until an event changes
+			<-r.Context().Done()
+			return
+		}
+		reply := replies[0]
+		replies = replies[1:]
+		lock.Unlock()
+
+		_, _ = w.Write([]byte(reply))
+	}))
+	defer server.Close()
+
+	profile := models.DeviceProfile{DeviceResources: []models.DeviceResource{
+		{Name: "Motion", Attributes: map[string]interface{}{"sunapi_event": "MotionDetection"}},
+		{Name: "Tamper", Attributes: map[string]interface{}{"sunapi_event": "Tampering"}},
+	}}
+
+	asyncCh := make(chan *sdkModels.AsyncValues, 2)
+	c := NewClient(asyncCh, logger.NewMockClient())
+	c.CameraInit(models.Device{Name: "Camera001"}, profile, strings.TrimPrefix(server.URL, "http://"), "admin", "pass")
+
+	expected := []map[string]bool{
+		{"Motion": false, "Tamper": false},
+		{"Motion": true},
+	}
+	for _, e := range expected {
+		select {
+		case av := <-asyncCh:
+			values := make(map[string]bool)
+			for _, cv := range av.CommandValues {
+				values[cv.DeviceResourceName] = cv.Value.(bool)
+			}
+			if av.DeviceName != "Camera001" || !reflect.DeepEqual(values, e) {
+				t.Errorf("Expected %v, Received %v", e, values)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("No async values, expected %v", e)
+		}
+	}
+
+	released := make(chan bool)
+	go func() {
+		c.CameraRelease(false)
+		close(released)
+	}()
+	select {
+	case <-released:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Event monitor not released")
+	}
+}
+
+func TestSendEventReleased(t *testing.T) {
+	tests := []struct {
+		name   string
+		closed bool
+	}{
+		{"blocked channel", false},
+		{"closed channel", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			asyncCh := make(chan *sdkModels.AsyncValues)
+			if test.closed {
+				close(asyncCh)
+			}
+			c := &SunapiClient{asyncChan: asyncCh, lc: logger.NewMockClient(), stop: make(chan bool)}
+			close(c.stop)
+
+			if c.sendEvent(models.Device{Name: "Camera001"}, nil) {
+				t.Errorf("Expected no async values to be sent by a released client")
+			}
+		})
+	}
+}
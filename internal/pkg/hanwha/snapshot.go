@@ -0,0 +1,74 @@
+package hanwha
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/client"
+)
+
+const snapshotFmtURL = "%s/video.cgi?%s"
+
+// snapshotURL returns the video.cgi URL of the JPEG snapshot described by the resource's
+// attributes: sunapi_channel, the video channel, 0 by default, sunapi_snapshot_profile, the
+// video profile, and sunapi_snapshot_resolution, e.g. "640x360".  The camera's defaults are used
+// for the profile and resolution if they aren't given.
+func snapshotURL(baseURL string, attributes map[string]interface{}) (string, error) {
+	channel, err := channelAttribute(attributes)
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{"msubmenu": {"snapshot"}, "action": {"view"}, "Channel": {strconv.Itoa(channel)}}
+
+	if profile, ok := attributes["sunapi_snapshot_profile"].(string); ok && profile != "" {
+		n, err := strconv.Atoi(profile)
+		if err != nil || n < 1 {
+			return "", fmt.Errorf("sunapi: invalid sunapi_snapshot_profile %s", profile)
+		}
+		query.Set("Profile", profile)
+	}
+
+	if resolution, ok := attributes["sunapi_snapshot_resolution"].(string); ok && resolution != "" {
+		if !client.ValidResolution(resolution) {
+			return "", fmt.Errorf("sunapi: invalid sunapi_snapshot_resolution %s", resolution)
+		}
+		query.Set("Resolution", resolution)
+	}
+
+	return fmt.Sprintf(snapshotFmtURL, baseURL, query.Encode()), nil
+}
+
+// readSnapshot reads a JPEG snapshot of a channel and video profile via SUNAPI.  SUNAPI replies
+// to a failed request with an "NG" text body rather than an error status, which is returned as
+// the error.
+func (c *SunapiClient) readSnapshot(req sdkModels.CommandRequest) (*sdkModels.CommandValue, error) {
+	snapURL, err := snapshotURL(c.baseURL, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.getWithContext(context.Background(), snapURL)
+	if err != nil {
+		return nil, err
+	}
+	contentType := resp.Header.Get("Content-Type")
+	data, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// errors are replied as text with status 200
+	if !strings.HasPrefix(contentType, "image/jpeg") {
+		if err = sunapiError(data); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("sunapi: unexpected snapshot content type %s", contentType)
+	}
+	return sdkModels.NewCommandValue(req.DeviceResourceName, common.ValueTypeBinary, data)
+}
@@ -0,0 +1,49 @@
+package hanwha
+
+import (
+	"testing"
+)
+
+func TestSnapshotURL(t *testing.T) {
+	tests := []struct {
+		attributes map[string]interface{}
+		expected   string
+		err        bool
+	}{
+		{map[string]interface{}{"sunapi_snapshot": "jpeg"}, "http://10.0.0.1/stw-cgi/video.cgi?Channel=0&action=view&msubmenu=snapshot", false},
+		{
+			map[string]interface{}{"sunapi_snapshot": "jpeg", "sunapi_channel": "1", "sunapi_snapshot_profile": "2", "sunapi_snapshot_resolution": "640x360"},
+			"http://10.0.0.1/stw-cgi/video.cgi?Channel=1&Profile=2&Resolution=640x360&action=view&msubmenu=snapshot",
+			false,
+		},
+		{map[string]interface{}{"sunapi_channel": "-1"}, "", true},
+		{map[string]interface{}{"sunapi_snapshot_profile": "0"}, "", true},
+		{map[string]interface{}{"sunapi_snapshot_resolution": "HD"}, "", true},
+	}
+
+	for _, test := range tests {
+		snapURL, err := snapshotURL("http://10.0.0.1/stw-cgi", test.attributes)
+		if test.err {
+			if err == nil {
+				t.Errorf("%v: Expected error", test.attributes)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: Unexpected error: %v", test.attributes, err)
+			continue
+		}
+		if snapURL != test.expected {
+			t.Errorf("%v: Expected %s, Received %s", test.attributes, test.expected, snapURL)
+		}
+	}
+}
+
+func TestSunapiError(t *testing.T) {
+	if err := sunapiError([]byte("NG\r\nError Code: 600\r\nError Details: Submenu Not Found\r\n")); err == nil || err.Error() != "sunapi: Error Code: 600 Error Details: Submenu Not Found" {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := sunapiError([]byte("OK\r\n")); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
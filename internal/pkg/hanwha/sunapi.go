@@ -0,0 +1,151 @@
+package hanwha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/client"
+	"github.com/edgexfoundry/device-camera-go/internal/pkg/digest"
+)
+
+const (
+	sunapiBaseFmtURL = "http://%s/stw-cgi"
+
+	retryWait = 5 * time.Second
+)
+
+var errCancelled = errors.New("cancelled")
+
+// SunapiClient is a client for Hanwha (Wisenet) cameras using SUNAPI.  The states of the events
+// mapped by sunapi_event attributes, e.g. motion, tampering, IVA and audio detection, are sent
+// as async readings whenever they change.
+type SunapiClient struct {
+	lc        logger.LoggingClient
+	asyncChan chan<- *sdkModels.AsyncValues
+	client    digest.Client
+	baseURL   string
+
+	events []eventResource
+
+	stop    chan bool
+	stopped chan bool
+}
+
+// NewClient returns a new SUNAPI Client
+func NewClient(asyncCh chan<- *sdkModels.AsyncValues, lc logger.LoggingClient) client.Client {
+	return &SunapiClient{asyncChan: asyncCh, lc: lc}
+}
+
+// CameraInit starts the event monitor for the camera when the device profile maps any events
+func (c *SunapiClient) CameraInit(edgexDevice models.Device, edgexProfile models.DeviceProfile, ipAddress string, username string, password string) {
+	if c.client == nil {
+		c.client = digest.NewDClient(&http.Client{}, username, password)
+	}
+
+	c.baseURL = fmt.Sprintf(sunapiBaseFmtURL, ipAddress)
+	c.events = eventResourcesFromProfile(edgexProfile, c.lc)
+
+	c.stop = make(chan bool)
+	c.stopped = make(chan bool)
+
+	if len(c.events) == 0 {
+		close(c.stopped)
+		return
+	}
+
+	go func() {
+		defer close(c.stopped)
+
+		for {
+			err := c.monitorEvents(edgexDevice)
+			if err == errCancelled {
+				return
+			}
+			c.lc.Errorf("SUNAPI event monitor of device '%s' failed: %s", edgexDevice.Name, err.Error())
+
+			select {
+			case <-c.stop:
+				return
+			case <-time.After(retryWait):
+			}
+		}
+	}()
+}
+
+// HandleReadCommand reads JPEG snapshots
+func (c *SunapiClient) HandleReadCommand(req sdkModels.CommandRequest) (*sdkModels.CommandValue, error) {
+	if _, ok := req.Attributes["sunapi_snapshot"]; ok {
+		return c.readSnapshot(req)
+	}
+	return nil, fmt.Errorf("sunapi: unrecognized read command")
+}
+
+// HandleWriteCommand is not implemented for SUNAPI--all commands that reach here are unexpected.
+func (c *SunapiClient) HandleWriteCommand(req sdkModels.CommandRequest, param *sdkModels.CommandValue) error {
+	return fmt.Errorf("sunapi: unrecognized write command")
+}
+
+// CameraRelease shuts down the event monitor
+func (c *SunapiClient) CameraRelease(force bool) {
+	close(c.stop)
+	client.WaitStopped(c.stopped, force)
+}
+
+// sendEvent sends the async readings unless the client is released.  Returns false when released.
+func (c *SunapiClient) sendEvent(edgexDevice models.Device, cvs []*sdkModels.CommandValue) bool {
+	return client.SendAsyncValues(c.asyncChan, c.stop, &sdkModels.AsyncValues{DeviceName: edgexDevice.Name, CommandValues: cvs})
+}
+
+func newBoolCommandValue(resourceName string, value bool) (*sdkModels.CommandValue, error) {
+	cv, err := sdkModels.NewCommandValue(resourceName, common.ValueTypeBool, value)
+	if err != nil {
+		return nil, err
+	}
+	cv.Origin = time.Now().UnixNano() / int64(time.Millisecond)
+	return cv, nil
+}
+
+// sunapiError returns the error of a SUNAPI reply, which starts with "NG" and is followed by the
+// error code and details
+func sunapiError(body []byte) error {
+	reply := strings.TrimSpace(string(body))
+	if !strings.HasPrefix(reply, "NG") {
+		return nil
+	}
+	return fmt.Errorf("sunapi: %s", strings.Join(strings.Fields(strings.TrimPrefix(reply, "NG")), " "))
+}
+
+func (c *SunapiClient) getWithContext(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sunapi: new request GET Error: %v", err.Error())
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sunapi: GET Error: %v", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("sunapi: status Error: %v", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func readBody(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("sunapi: read Error: %v", err.Error())
+	}
+	return body, nil
+}
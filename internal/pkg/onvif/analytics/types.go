@@ -0,0 +1,72 @@
+// Package analytics holds the request and response types of the ONVIF Analytics service
+// (http://www.onvif.org/ver20/analytics/wsdl) which are not provided by onvif4go.  Its name
+// routes the requests, see package onvif.
+package analytics
+
+// SchemaNamespace is the namespace bound to the "tt" prefix used in Config types
+const SchemaNamespace = "http://www.onvif.org/ver10/schema"
+
+// Config is a single analytics module or rule, e.g. tt:CellMotionEngine or tt:CellMotionDetector.
+// Type is a QName, so Prefix must declare its "tt" prefix when the Config is sent to a camera.
+type Config struct {
+	Prefix     string   `xml:"xmlns:tt,attr,omitempty" json:"-"`
+	Name       string   `xml:"Name,attr"`
+	Type       string   `xml:"Type,attr"`
+	Parameters ItemList `xml:"http://www.onvif.org/ver10/schema Parameters"`
+}
+
+// ItemList holds the parameters of a Config. Unlike onvif.ItemList it can be marshaled, which
+// is required to send modified modules and rules back to the camera.
+type ItemList struct {
+	SimpleItem  []SimpleItem  `xml:"http://www.onvif.org/ver10/schema SimpleItem"`
+	ElementItem []ElementItem `xml:"http://www.onvif.org/ver10/schema ElementItem"`
+}
+
+// SimpleItem is a name/value parameter pair
+type SimpleItem struct {
+	Name  string `xml:"Name,attr"`
+	Value string `xml:"Value,attr"`
+}
+
+// ElementItem is a complex parameter, e.g. the tt:CellLayout of a cell motion engine. Its
+// content is kept as raw XML.
+type ElementItem struct {
+	Name  string `xml:"Name,attr"`
+	Inner string `xml:",innerxml"`
+}
+
+type GetAnalyticsModules struct {
+	XMLName            string `xml:"http://www.onvif.org/ver20/analytics/wsdl GetAnalyticsModules"`
+	ConfigurationToken string `xml:"http://www.onvif.org/ver20/analytics/wsdl ConfigurationToken"`
+}
+
+type GetAnalyticsModulesResponse struct {
+	AnalyticsModule []Config
+}
+
+type ModifyAnalyticsModules struct {
+	XMLName            string   `xml:"http://www.onvif.org/ver20/analytics/wsdl ModifyAnalyticsModules"`
+	ConfigurationToken string   `xml:"http://www.onvif.org/ver20/analytics/wsdl ConfigurationToken"`
+	AnalyticsModule    []Config `xml:"http://www.onvif.org/ver20/analytics/wsdl AnalyticsModule"`
+}
+
+type ModifyAnalyticsModulesResponse struct {
+}
+
+type GetRules struct {
+	XMLName            string `xml:"http://www.onvif.org/ver20/analytics/wsdl GetRules"`
+	ConfigurationToken string `xml:"http://www.onvif.org/ver20/analytics/wsdl ConfigurationToken"`
+}
+
+type GetRulesResponse struct {
+	Rule []Config
+}
+
+type ModifyRules struct {
+	XMLName            string   `xml:"http://www.onvif.org/ver20/analytics/wsdl ModifyRules"`
+	ConfigurationToken string   `xml:"http://www.onvif.org/ver20/analytics/wsdl ConfigurationToken"`
+	Rule               []Config `xml:"http://www.onvif.org/ver20/analytics/wsdl Rule"`
+}
+
+type ModifyRulesResponse struct {
+}
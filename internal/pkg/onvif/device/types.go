@@ -0,0 +1,94 @@
+// Package device holds request and response types of the ONVIF Device service
+// (http://www.onvif.org/ver10/device/wsdl) which onvif4go declares in a form that cannot be
+// sent to a camera: its IP address filter holds a single address of each kind and its binary
+// data, such as policy files and certificates, declares an unbound xmime prefix.  Requests are
+// routed to the Device service by the package name, as described in package onvif.
+package device
+
+// IPAddressFilter lists the addresses which are allowed or denied access to the camera,
+// depending on Type, which is "Allow" or "Deny"
+type IPAddressFilter struct {
+	Type        string                `xml:"http://www.onvif.org/ver10/schema Type"`
+	IPv4Address []PrefixedIPv4Address `xml:"http://www.onvif.org/ver10/schema IPv4Address"`
+	IPv6Address []PrefixedIPv6Address `xml:"http://www.onvif.org/ver10/schema IPv6Address"`
+}
+
+// PrefixedIPv4Address is an IPv4 address range, e.g. 192.168.2.0 with a prefix length of 24
+type PrefixedIPv4Address struct {
+	Address      string `xml:"http://www.onvif.org/ver10/schema Address"`
+	PrefixLength int    `xml:"http://www.onvif.org/ver10/schema PrefixLength"`
+}
+
+// PrefixedIPv6Address is an IPv6 address range
+type PrefixedIPv6Address struct {
+	Address      string `xml:"http://www.onvif.org/ver10/schema Address"`
+	PrefixLength int    `xml:"http://www.onvif.org/ver10/schema PrefixLength"`
+}
+
+// BinaryData is a file exchanged with the camera.  Data is base64 encoded.
+type BinaryData struct {
+	ContentType string `xml:"http://www.w3.org/2005/05/xmlmime contentType,attr,omitempty"`
+	Data        string `xml:"http://www.onvif.org/ver10/schema Data"`
+}
+
+type GetIPAddressFilter struct {
+	XMLName string `xml:"http://www.onvif.org/ver10/device/wsdl GetIPAddressFilter"`
+}
+
+type GetIPAddressFilterResponse struct {
+	IPAddressFilter IPAddressFilter
+}
+
+type SetIPAddressFilter struct {
+	XMLName         string          `xml:"http://www.onvif.org/ver10/device/wsdl SetIPAddressFilter"`
+	IPAddressFilter IPAddressFilter `xml:"http://www.onvif.org/ver10/device/wsdl IPAddressFilter"`
+}
+
+type SetIPAddressFilterResponse struct {
+}
+
+type AddIPAddressFilter struct {
+	XMLName         string          `xml:"http://www.onvif.org/ver10/device/wsdl AddIPAddressFilter"`
+	IPAddressFilter IPAddressFilter `xml:"http://www.onvif.org/ver10/device/wsdl IPAddressFilter"`
+}
+
+type AddIPAddressFilterResponse struct {
+}
+
+type RemoveIPAddressFilter struct {
+	XMLName         string          `xml:"http://www.onvif.org/ver10/device/wsdl RemoveIPAddressFilter"`
+	IPAddressFilter IPAddressFilter `xml:"http://www.onvif.org/ver10/device/wsdl IPAddressFilter"`
+}
+
+type RemoveIPAddressFilterResponse struct {
+}
+
+type GetAccessPolicy struct {
+	XMLName string `xml:"http://www.onvif.org/ver10/device/wsdl GetAccessPolicy"`
+}
+
+type GetAccessPolicyResponse struct {
+	PolicyFile BinaryData
+}
+
+type SetAccessPolicy struct {
+	XMLName    string     `xml:"http://www.onvif.org/ver10/device/wsdl SetAccessPolicy"`
+	PolicyFile BinaryData `xml:"http://www.onvif.org/ver10/device/wsdl PolicyFile"`
+}
+
+type SetAccessPolicyResponse struct {
+}
+
+// Certificate is an X.509 certificate of the camera.  Its Data is base64 encoded DER.
+type Certificate struct {
+	CertificateID string     `xml:"http://www.onvif.org/ver10/schema CertificateID"`
+	Certificate   BinaryData `xml:"http://www.onvif.org/ver10/schema Certificate"`
+}
+
+type LoadCertificates struct {
+	XMLName        string        `xml:"http://www.onvif.org/ver10/device/wsdl LoadCertificates"`
+	NVTCertificate []Certificate `xml:"http://www.onvif.org/ver10/device/wsdl NVTCertificate"`
+}
+
+type LoadCertificatesResponse struct {
+}
@@ -0,0 +1,7 @@
+// Package onvif groups the request and response types of ONVIF services which onvif4go doesn't
+// provide, or declares in a form that cannot be sent to a camera, in one subpackage per service.
+//
+// Subpackages of services called via onvif4go's OnvifDevice.Call are deliberately named after
+// their service: Call routes a request to a service endpoint by the name of the package its type
+// is declared in, so e.g. Device service types must be declared in a package named device.
+package onvif
@@ -0,0 +1,79 @@
+// Package thermal holds the request and response types of the ONVIF Thermal service
+// (http://www.onvif.org/ver10/thermal/wsdl), which are not provided by onvif4go.
+package thermal
+
+// Namespace is the namespace of the ONVIF Thermal service, as reported by GetServices
+const Namespace = "http://www.onvif.org/ver10/thermal/wsdl"
+
+// Configuration is the thermal configuration of a video source
+type Configuration struct {
+	ColorPalette ColorPalette `xml:"http://www.onvif.org/ver10/thermal/wsdl ColorPalette"`
+	Polarity     string       `xml:"http://www.onvif.org/ver10/thermal/wsdl Polarity"`
+	NUCTable     *NUCTable    `xml:"http://www.onvif.org/ver10/thermal/wsdl NUCTable,omitempty"`
+	Cooler       *Cooler      `xml:"http://www.onvif.org/ver10/thermal/wsdl Cooler,omitempty"`
+}
+
+// ColorPalette describes the mapping of temperatures to colors
+type ColorPalette struct {
+	Token string `xml:"token,attr"`
+	Type  string `xml:"Type,attr"`
+	Name  string `xml:"http://www.onvif.org/ver10/thermal/wsdl Name"`
+}
+
+// NUCTable is a non-uniformity correction table
+type NUCTable struct {
+	Token    string   `xml:"token,attr"`
+	LowTemp  *float32 `xml:"LowTemperature,attr,omitempty"`
+	HighTemp *float32 `xml:"HighTemperature,attr,omitempty"`
+	Name     string   `xml:"http://www.onvif.org/ver10/thermal/wsdl Name"`
+}
+
+// Cooler describes the sensor cooler of cooled thermal cameras
+type Cooler struct {
+	Enabled bool     `xml:"http://www.onvif.org/ver10/thermal/wsdl Enabled"`
+	RunTime *float32 `xml:"http://www.onvif.org/ver10/thermal/wsdl RunTime,omitempty"`
+}
+
+// RadiometryConfiguration holds the parameters used for temperature measurements
+type RadiometryConfiguration struct {
+	RadiometryGlobalParameters *RadiometryGlobalParameters `xml:"http://www.onvif.org/ver10/thermal/wsdl RadiometryGlobalParameters,omitempty"`
+}
+
+// RadiometryGlobalParameters are the scene parameters applied to all measurements
+type RadiometryGlobalParameters struct {
+	ReflectedAmbientTemperature float32  `xml:"http://www.onvif.org/ver10/thermal/wsdl ReflectedAmbientTemperature"`
+	Emissivity                  float32  `xml:"http://www.onvif.org/ver10/thermal/wsdl Emissivity"`
+	DistanceToObject            float32  `xml:"http://www.onvif.org/ver10/thermal/wsdl DistanceToObject"`
+	RelativeHumidity            *float32 `xml:"http://www.onvif.org/ver10/thermal/wsdl RelativeHumidity,omitempty"`
+	AtmosphericTemperature      *float32 `xml:"http://www.onvif.org/ver10/thermal/wsdl AtmosphericTemperature,omitempty"`
+	AtmosphericTransmittance    *float32 `xml:"http://www.onvif.org/ver10/thermal/wsdl AtmosphericTransmittance,omitempty"`
+	ExtOpticsTemperature        *float32 `xml:"http://www.onvif.org/ver10/thermal/wsdl ExtOpticsTemperature,omitempty"`
+	ExtOpticsTransmittance      *float32 `xml:"http://www.onvif.org/ver10/thermal/wsdl ExtOpticsTransmittance,omitempty"`
+}
+
+type GetConfiguration struct {
+	XMLName          string `xml:"http://www.onvif.org/ver10/thermal/wsdl GetConfiguration"`
+	VideoSourceToken string `xml:"http://www.onvif.org/ver10/thermal/wsdl VideoSourceToken"`
+}
+
+type GetConfigurationResponse struct {
+	Configuration Configuration
+}
+
+type SetConfiguration struct {
+	XMLName          string        `xml:"http://www.onvif.org/ver10/thermal/wsdl SetConfiguration"`
+	VideoSourceToken string        `xml:"http://www.onvif.org/ver10/thermal/wsdl VideoSourceToken"`
+	Configuration    Configuration `xml:"http://www.onvif.org/ver10/thermal/wsdl Configuration"`
+}
+
+type SetConfigurationResponse struct {
+}
+
+type GetRadiometryConfiguration struct {
+	XMLName          string `xml:"http://www.onvif.org/ver10/thermal/wsdl GetRadiometryConfiguration"`
+	VideoSourceToken string `xml:"http://www.onvif.org/ver10/thermal/wsdl VideoSourceToken"`
+}
+
+type GetRadiometryConfigurationResponse struct {
+	Configuration RadiometryConfiguration
+}
@@ -0,0 +1,107 @@
+// Package reading converts the text values reported by cameras, e.g. ONVIF event items and Axis
+// parameters, to readings of the value type of their device resources.
+package reading
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+// Options adapts the conversion to the forms used by a manufacturer
+type Options struct {
+	// VendorBooleans accepts yes/no and on/off as booleans, in addition to the forms of
+	// strconv.ParseBool
+	VendorBooleans bool
+}
+
+// FromString converts a value to a reading of the given value type.  Unsupported value types
+// are an error.
+func FromString(resourceName string, valueType string, value string, opts Options) (*sdkModels.CommandValue, error) {
+	value = strings.TrimSpace(value)
+
+	switch valueType {
+	case common.ValueTypeString:
+		return sdkModels.NewCommandValue(resourceName, valueType, value)
+	case common.ValueTypeBool:
+		b, err := parseBool(value, opts)
+		if err != nil {
+			return nil, err
+		}
+		return sdkModels.NewCommandValue(resourceName, valueType, b)
+	case common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64:
+		n, err := strconv.ParseInt(value, 10, bitSize(valueType))
+		if err != nil {
+			return nil, err
+		}
+		switch valueType {
+		case common.ValueTypeInt8:
+			return sdkModels.NewCommandValue(resourceName, valueType, int8(n))
+		case common.ValueTypeInt16:
+			return sdkModels.NewCommandValue(resourceName, valueType, int16(n))
+		case common.ValueTypeInt32:
+			return sdkModels.NewCommandValue(resourceName, valueType, int32(n))
+		}
+		return sdkModels.NewCommandValue(resourceName, valueType, n)
+	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64:
+		n, err := strconv.ParseUint(value, 10, bitSize(valueType))
+		if err != nil {
+			return nil, err
+		}
+		switch valueType {
+		case common.ValueTypeUint8:
+			return sdkModels.NewCommandValue(resourceName, valueType, uint8(n))
+		case common.ValueTypeUint16:
+			return sdkModels.NewCommandValue(resourceName, valueType, uint16(n))
+		case common.ValueTypeUint32:
+			return sdkModels.NewCommandValue(resourceName, valueType, uint32(n))
+		}
+		return sdkModels.NewCommandValue(resourceName, valueType, n)
+	case common.ValueTypeFloat32:
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return nil, err
+		}
+		return sdkModels.NewCommandValue(resourceName, valueType, float32(f))
+	case common.ValueTypeFloat64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		return sdkModels.NewCommandValue(resourceName, valueType, f)
+	}
+
+	return nil, fmt.Errorf("unsupported value type %s", valueType)
+}
+
+func parseBool(value string, opts Options) (bool, error) {
+	if opts.VendorBooleans {
+		switch strings.ToLower(value) {
+		case "yes", "on":
+			return true, nil
+		case "no", "off":
+			return false, nil
+		}
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %s", value)
+	}
+	return b, nil
+}
+
+func bitSize(valueType string) int {
+	switch valueType {
+	case common.ValueTypeInt8, common.ValueTypeUint8:
+		return 8
+	case common.ValueTypeInt16, common.ValueTypeUint16:
+		return 16
+	case common.ValueTypeInt32, common.ValueTypeUint32:
+		return 32
+	}
+	return 64
+}
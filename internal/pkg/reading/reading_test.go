@@ -0,0 +1,54 @@
+package reading
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+func TestFromString(t *testing.T) {
+	vendor := Options{VendorBooleans: true}
+
+	tests := []struct {
+		name          string
+		valueType     string
+		value         string
+		opts          Options
+		expected      interface{}
+		expectedError bool
+	}{
+		{"string", common.ValueTypeString, "1920x1080", Options{}, "1920x1080", false},
+		{"bool", common.ValueTypeBool, "true", Options{}, true, false},
+		{"bool 0", common.ValueTypeBool, "0", Options{}, false, false},
+		{"vendor bool yes", common.ValueTypeBool, "yes", vendor, true, false},
+		{"vendor bool off", common.ValueTypeBool, "Off", vendor, false, false},
+		{"vendor bool 1", common.ValueTypeBool, "1", vendor, true, false},
+		{"yes without vendor booleans", common.ValueTypeBool, "yes", Options{}, nil, true},
+		{"invalid bool", common.ValueTypeBool, "maybe", vendor, nil, true},
+		{"int8", common.ValueTypeInt8, "-3", Options{}, int8(-3), false},
+		{"int16", common.ValueTypeInt16, "300", Options{}, int16(300), false},
+		{"int32", common.ValueTypeInt32, " 70000 ", Options{}, int32(70000), false},
+		{"int64", common.ValueTypeInt64, "-5000000000", Options{}, int64(-5000000000), false},
+		{"uint8", common.ValueTypeUint8, "255", Options{}, uint8(255), false},
+		{"uint16", common.ValueTypeUint16, "8080", Options{}, uint16(8080), false},
+		{"uint32", common.ValueTypeUint32, "4000000000", Options{}, uint32(4000000000), false},
+		{"uint64", common.ValueTypeUint64, "5000000000", Options{}, uint64(5000000000), false},
+		{"float32", common.ValueTypeFloat32, "35.5", Options{}, float32(35.5), false},
+		{"float64", common.ValueTypeFloat64, "80.25", Options{}, 80.25, false},
+		{"uint8 out of range", common.ValueTypeUint8, "300", Options{}, nil, true},
+		{"invalid int", common.ValueTypeInt32, "warm", Options{}, nil, true},
+		{"unsupported type", common.ValueTypeBinary, "data", vendor, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cv, err := FromString("Reading", test.valueType, test.value, test.opts)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err == nil && (cv.Type != test.valueType || cv.Value != test.expected) {
+				t.Errorf("Expected: '%v' %s, Result: '%v' %s", test.expected, test.valueType, cv.Value, cv.Type)
+			}
+		})
+	}
+}
@@ -0,0 +1,439 @@
+// Package rtsp implements the small subset of RTSP needed to send audio to a camera through
+// the ONVIF audio backchannel (ONVIF Streaming Specification, section 5.3), and to receive the
+// camera's ONVIF metadata stream.
+package rtsp
+
+import (
+	"bufio"
+	"crypto/md5" //nolint:gosec
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	backchannelRequire = "www.onvif.org/ver20/backchannel"
+	defaultPort        = "554"
+	dialTimeout        = 10 * time.Second
+
+	// G.711 µ-law is sampled at 8 kHz with one byte per sample, and sent in 20 ms packets
+	pcmuPayloadType = 0
+	pcmuClockRate   = 8000
+	packetDuration  = 20 * time.Millisecond
+	packetSize      = pcmuClockRate / int(time.Second/packetDuration)
+)
+
+// Client is a single RTSP session with a camera
+type Client struct {
+	uri      *url.URL
+	username string
+	password string
+
+	conn   net.Conn
+	reader *textproto.Reader
+	cseq   int
+
+	// require is the RTSP feature tag sent with each request, if any
+	require   string
+	session   string
+	challenge map[string]string
+
+	// aggregate is the control URL of the whole session, to which PLAY, TEARDOWN and keep-alives
+	// are sent
+	aggregate string
+}
+
+type response struct {
+	statusCode int
+	header     textproto.MIMEHeader
+	body       []byte
+}
+
+// NewClient returns a Client for the given rtsp:// URI.  Credentials embedded in the URI take
+// precedence over the given username and password.
+func NewClient(uri string, username string, password string) (*Client, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("rtsp: invalid URI: %v", err.Error())
+	}
+	if u.Scheme != "rtsp" {
+		return nil, fmt.Errorf("rtsp: unsupported URI scheme '%s'", u.Scheme)
+	}
+
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+		u.User = nil
+	}
+
+	return &Client{uri: u, username: username, password: password}, nil
+}
+
+// PlayBackchannel sends G.711 µ-law audio to the camera's audio backchannel, pacing the RTP
+// packets in real time.  It returns once all audio has been sent and the session is torn down.
+func (c *Client) PlayBackchannel(audio []byte) error {
+	c.require = backchannelRequire
+	err := c.dial()
+	if err != nil {
+		return err
+	}
+	defer c.conn.Close()
+
+	resp, err := c.describe()
+	if err != nil {
+		return err
+	}
+
+	control, payloadType, err := backchannelTrack(string(resp.body))
+	if err != nil {
+		return err
+	}
+
+	resp, err = c.do("SETUP", c.controlURL(resp, control), map[string]string{"Transport": "RTP/AVP/TCP;unicast;interleaved=0-1"})
+	if err != nil {
+		return err
+	}
+
+	c.session = strings.Split(resp.header.Get("Session"), ";")[0]
+	channel := interleavedChannel(resp.header.Get("Transport"))
+
+	_, err = c.do("PLAY", c.aggregate, map[string]string{"Range": "npt=0.000-"})
+	if err != nil {
+		return err
+	}
+
+	// the camera may send RTCP reports while we stream, which are discarded until the session
+	// ends; the connection is closed first, so the reader always ends with the session
+	ended := make(chan struct{})
+	var readErr error
+	go func() {
+		defer close(ended)
+		_, readErr = io.Copy(ioutil.Discard, c.reader.R)
+	}()
+	defer func() {
+		c.conn.Close()
+		<-ended
+	}()
+
+	err = c.sendAudio(channel, payloadType, audio, ended)
+	if err != nil {
+		select {
+		case <-ended:
+			if readErr == nil {
+				readErr = io.EOF
+			}
+			return fmt.Errorf("rtsp: camera ended the backchannel session: %v", readErr)
+		default:
+			return err
+		}
+	}
+
+	return c.send("TEARDOWN", c.aggregate, nil)
+}
+
+// describe requests the SDP description of the URI and resolves the aggregate control URL of
+// the session from it
+func (c *Client) describe() (*response, error) {
+	resp, err := c.do("DESCRIBE", c.uri.String(), map[string]string{"Accept": "application/sdp"})
+	if err != nil {
+		return nil, err
+	}
+
+	c.aggregate = c.controlURL(resp, sessionControl(string(resp.body)))
+	return resp, nil
+}
+
+func (c *Client) dial() error {
+	host := c.uri.Host
+	if c.uri.Port() == "" {
+		host = net.JoinHostPort(c.uri.Hostname(), defaultPort)
+	}
+
+	conn, err := net.DialTimeout("tcp", host, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("rtsp: dial: %v", err.Error())
+	}
+
+	c.conn = conn
+	c.reader = textproto.NewReader(bufio.NewReader(conn))
+	return nil
+}
+
+// do sends a request and reads its response, retrying once with credentials if the camera
+// answers 401 Unauthorized
+func (c *Client) do(method string, uri string, headers map[string]string) (*response, error) {
+	err := c.send(method, uri, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.readResponse()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.statusCode == 401 && c.challenge == nil && c.username != "" {
+		c.challenge = parseChallenge(resp.header.Get("WWW-Authenticate"))
+
+		err = c.send(method, uri, headers)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.statusCode != 200 {
+		return nil, fmt.Errorf("rtsp: %s failed with status %d", method, resp.statusCode)
+	}
+
+	return resp, nil
+}
+
+func (c *Client) send(method string, uri string, headers map[string]string) error {
+	c.cseq++
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s RTSP/1.0\r\n", method, uri)
+	fmt.Fprintf(&b, "CSeq: %d\r\n", c.cseq)
+	if c.require != "" {
+		fmt.Fprintf(&b, "Require: %s\r\n", c.require)
+	}
+	if c.session != "" {
+		fmt.Fprintf(&b, "Session: %s\r\n", c.session)
+	}
+	if c.challenge != nil {
+		fmt.Fprintf(&b, "Authorization: %s\r\n", c.authorization(method, uri))
+	}
+	for k, v := range headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+	}
+	b.WriteString("\r\n")
+
+	_, err := c.conn.Write([]byte(b.String()))
+	if err != nil {
+		return fmt.Errorf("rtsp: sending %s: %v", method, err.Error())
+	}
+	return nil
+}
+
+func (c *Client) readResponse() (*response, error) {
+	statusLine, err := c.reader.ReadLine()
+	if err != nil {
+		return nil, fmt.Errorf("rtsp: reading response: %v", err.Error())
+	}
+
+	fields := strings.SplitN(statusLine, " ", 3)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "RTSP/") {
+		return nil, fmt.Errorf("rtsp: malformed status line '%s'", statusLine)
+	}
+
+	statusCode, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("rtsp: malformed status code '%s'", fields[1])
+	}
+
+	header, err := c.reader.ReadMIMEHeader()
+	if err != nil {
+		return nil, fmt.Errorf("rtsp: reading headers: %v", err.Error())
+	}
+
+	resp := &response{statusCode: statusCode, header: header}
+	if length, _ := strconv.Atoi(header.Get("Content-Length")); length > 0 {
+		resp.body = make([]byte, length)
+		_, err = io.ReadFull(c.reader.R, resp.body)
+		if err != nil {
+			return nil, fmt.Errorf("rtsp: reading body: %v", err.Error())
+		}
+	}
+
+	return resp, nil
+}
+
+// sendAudio sends the audio as RTP packets interleaved on the RTSP connection, until all audio
+// is sent or the session has ended
+func (c *Client) sendAudio(channel byte, payloadType byte, audio []byte, ended <-chan struct{}) error {
+	ssrc := rand.Uint32()        //nolint:gosec
+	seq := uint16(rand.Uint32()) //nolint:gosec
+	timestamp := rand.Uint32()   //nolint:gosec
+
+	ticker := time.NewTicker(packetDuration)
+	defer ticker.Stop()
+
+	for offset := 0; offset < len(audio); offset += packetSize {
+		end := offset + packetSize
+		if end > len(audio) {
+			end = len(audio)
+		}
+		payload := audio[offset:end]
+
+		packet := make([]byte, 4+12+len(payload))
+		packet[0] = '$'
+		packet[1] = channel
+		binary.BigEndian.PutUint16(packet[2:4], uint16(12+len(payload)))
+
+		rtp := packet[4:]
+		rtp[0] = 0x80 // RTP version 2
+		rtp[1] = payloadType
+		if offset == 0 {
+			rtp[1] |= 0x80 // marker bit on the first packet of a talkspurt
+		}
+		binary.BigEndian.PutUint16(rtp[2:4], seq)
+		binary.BigEndian.PutUint32(rtp[4:8], timestamp)
+		binary.BigEndian.PutUint32(rtp[8:12], ssrc)
+		copy(rtp[12:], payload)
+
+		_, err := c.conn.Write(packet)
+		if err != nil {
+			return fmt.Errorf("rtsp: sending audio: %v", err.Error())
+		}
+
+		seq++
+		timestamp += uint32(len(payload))
+		select {
+		case <-ticker.C:
+		case <-ended:
+			return fmt.Errorf("rtsp: session ended while sending audio")
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) controlURL(resp *response, control string) string {
+	if strings.HasPrefix(control, "rtsp://") {
+		return control
+	}
+
+	base := resp.header.Get("Content-Base")
+	if base == "" {
+		base = c.uri.String()
+	}
+	if control == "" || control == "*" {
+		return base
+	}
+	return strings.TrimSuffix(base, "/") + "/" + control
+}
+
+func (c *Client) authorization(method string, uri string) string {
+	if c.challenge["scheme"] != "digest" {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.username+":"+c.password))
+	}
+
+	ha1 := md5Hex(c.username + ":" + c.challenge["realm"] + ":" + c.password)
+	ha2 := md5Hex(method + ":" + uri)
+	digest := md5Hex(ha1 + ":" + c.challenge["nonce"] + ":" + ha2)
+	return fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+		c.username, c.challenge["realm"], c.challenge["nonce"], uri, digest)
+}
+
+// sessionControl returns the session-level control attribute of an SDP description, which
+// precedes the first media description, or "" if there is none
+func sessionControl(sdp string) string {
+	for _, line := range strings.Split(sdp, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "m=") {
+			break
+		}
+		if strings.HasPrefix(line, "a=control:") {
+			return strings.TrimPrefix(line, "a=control:")
+		}
+	}
+	return ""
+}
+
+// backchannelTrack finds the sendonly PCMU audio track in an SDP description and returns its
+// control attribute and RTP payload type
+func backchannelTrack(sdp string) (string, byte, error) {
+	var inAudio, sendOnly, pcmu bool
+	var control string
+	var payloadType byte
+
+	done := func() bool { return inAudio && sendOnly && pcmu }
+
+	for _, line := range strings.Split(sdp, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "m="):
+			if done() {
+				return control, payloadType, nil
+			}
+			inAudio, sendOnly, pcmu, control = false, false, false, ""
+
+			fields := strings.Fields(line)
+			if fields[0] != "m=audio" || len(fields) < 4 {
+				continue
+			}
+			inAudio = true
+			for _, format := range fields[3:] {
+				if format == strconv.Itoa(pcmuPayloadType) {
+					pcmu, payloadType = true, pcmuPayloadType
+				}
+			}
+		case !inAudio:
+			continue
+		case line == "a=sendonly":
+			sendOnly = true
+		case strings.HasPrefix(line, "a=control:"):
+			control = strings.TrimPrefix(line, "a=control:")
+		case strings.HasPrefix(line, "a=rtpmap:"):
+			fields := strings.Fields(strings.TrimPrefix(line, "a=rtpmap:"))
+			if len(fields) == 2 && strings.EqualFold(fields[1], "PCMU/8000") {
+				pt, err := strconv.Atoi(fields[0])
+				if err == nil {
+					pcmu, payloadType = true, byte(pt)
+				}
+			}
+		}
+	}
+
+	if done() {
+		return control, payloadType, nil
+	}
+	return "", 0, fmt.Errorf("rtsp: camera offers no G.711 µ-law audio backchannel")
+}
+
+func interleavedChannel(transport string) byte {
+	for _, param := range strings.Split(transport, ";") {
+		if value := strings.TrimPrefix(param, "interleaved="); value != param {
+			channel, err := strconv.Atoi(strings.Split(value, "-")[0])
+			if err == nil {
+				return byte(channel)
+			}
+		}
+	}
+	return 0
+}
+
+func parseChallenge(header string) map[string]string {
+	challenge := map[string]string{}
+
+	scheme, params, _ := strings.Cut(header, " ")
+	challenge["scheme"] = strings.ToLower(scheme)
+	for _, param := range strings.Split(params, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found {
+			challenge[key] = strings.Trim(value, `"`)
+		}
+	}
+	return challenge
+}
+
+func md5Hex(text string) string {
+	sum := md5.Sum([]byte(text)) //nolint:gosec
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,368 @@
+package rtsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+const testSDP = "v=0\r\n" +
+	"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+	"s=stand-in\r\n" +
+	"t=0 0\r\n" +
+	"a=control:aggregate\r\n" +
+	"m=video 0 RTP/AVP 96\r\n" +
+	"a=control:video\r\n" +
+	"a=recvonly\r\n" +
+	"m=audio 0 RTP/AVP 0\r\n" +
+	"a=control:audioback\r\n" +
+	"a=rtpmap:0 PCMU/8000\r\n" +
+	"a=sendonly\r\n"
+
+// standIn is a local RTSP server that accepts a single backchannel session and collects
+// the audio payload sent to it
+type standIn struct {
+	listener net.Listener
+	methods  []string
+	audio    bytes.Buffer
+	err      error
+	done     chan struct{}
+}
+
+func newStandIn(t *testing.T) *standIn {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	s := &standIn{listener: l, done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		s.err = s.serve()
+	}()
+	return s
+}
+
+func (s *standIn) serve() error {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	tp := textproto.NewReader(r)
+	authorized := false
+
+	for {
+		b, err := r.Peek(1)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if b[0] == '$' {
+			header := make([]byte, 4)
+			if _, err = io.ReadFull(r, header); err != nil {
+				return err
+			}
+			packet := make([]byte, binary.BigEndian.Uint16(header[2:4]))
+			if _, err = io.ReadFull(r, packet); err != nil {
+				return err
+			}
+			if packet[1]&0x7f != pcmuPayloadType {
+				return fmt.Errorf("unexpected payload type %d", packet[1]&0x7f)
+			}
+			s.audio.Write(packet[12:])
+			continue
+		}
+
+		line, err := tp.ReadLine()
+		if err != nil {
+			return err
+		}
+		header, err := tp.ReadMIMEHeader()
+		if err != nil {
+			return err
+		}
+		method := strings.Fields(line)[0]
+		s.methods = append(s.methods, method)
+
+		if header.Get("Require") != backchannelRequire {
+			return fmt.Errorf("%s without backchannel Require header", method)
+		}
+
+		cseq := header.Get("CSeq")
+		if !authorized {
+			if !strings.HasPrefix(header.Get("Authorization"), "Digest username=\"admin\"") {
+				fmt.Fprintf(conn, "RTSP/1.0 401 Unauthorized\r\nCSeq: %s\r\nWWW-Authenticate: Digest realm=\"stand-in\", nonce=\"abc\"\r\n\r\n", cseq)
+				continue
+			}
+			authorized = true
+		}
+
+		switch method {
+		case "DESCRIBE":
+			fmt.Fprintf(conn, "RTSP/1.0 200 OK\r\nCSeq: %s\r\nContent-Base: rtsp://%s/stream/\r\nContent-Length: %d\r\n\r\n%s",
+				cseq, s.listener.Addr(), len(testSDP), testSDP)
+		case "SETUP":
+			if !strings.HasSuffix(line, "/stream/audioback RTSP/1.0") {
+				return fmt.Errorf("SETUP of wrong track: %s", line)
+			}
+			fmt.Fprintf(conn, "RTSP/1.0 200 OK\r\nCSeq: %s\r\nSession: 1234;timeout=60\r\nTransport: RTP/AVP/TCP;unicast;interleaved=2-3\r\n\r\n", cseq)
+		case "PLAY", "TEARDOWN":
+			if header.Get("Session") != "1234" {
+				return fmt.Errorf("%s without session", method)
+			}
+			if !strings.HasSuffix(line, "/stream/aggregate RTSP/1.0") {
+				return fmt.Errorf("%s not sent to the aggregate control URL: %s", method, line)
+			}
+			fmt.Fprintf(conn, "RTSP/1.0 200 OK\r\nCSeq: %s\r\n\r\n", cseq)
+			if method == "TEARDOWN" {
+				return nil
+			}
+		}
+	}
+}
+
+func TestPlayBackchannel(t *testing.T) {
+	s := newStandIn(t)
+	defer s.listener.Close()
+
+	audio := make([]byte, 3*packetSize+10)
+	for i := range audio {
+		audio[i] = byte(i)
+	}
+
+	c, err := NewClient(fmt.Sprintf("rtsp://admin:secret@%s/stream", s.listener.Addr()), "", "")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	err = c.PlayBackchannel(audio)
+	if err != nil {
+		t.Fatalf("PlayBackchannel: %v", err)
+	}
+
+	<-s.done
+	if s.err != nil {
+		t.Fatalf("stand-in: %v", s.err)
+	}
+
+	expectedMethods := "DESCRIBE,DESCRIBE,SETUP,PLAY,TEARDOWN"
+	if methods := strings.Join(s.methods, ","); methods != expectedMethods {
+		t.Errorf("Expected methods '%s', Received '%s'", expectedMethods, methods)
+	}
+	if !bytes.Equal(s.audio.Bytes(), audio) {
+		t.Errorf("Audio received by stand-in doesn't match the audio sent")
+	}
+}
+
+func TestBackchannelTrack(t *testing.T) {
+	tests := []struct {
+		name            string
+		sdp             string
+		expectedControl string
+		expectedPT      byte
+		expectedError   bool
+	}{
+		{
+			name:            "static payload type",
+			sdp:             testSDP,
+			expectedControl: "audioback",
+			expectedPT:      0,
+		},
+		{
+			name:            "dynamic payload type",
+			sdp:             "m=audio 0 RTP/AVP 97\na=rtpmap:97 PCMU/8000\na=control:back\na=sendonly\nm=video 0 RTP/AVP 96\n",
+			expectedControl: "back",
+			expectedPT:      97,
+		},
+		{
+			name:          "receive only audio",
+			sdp:           "m=audio 0 RTP/AVP 0\na=control:audio\na=recvonly\n",
+			expectedError: true,
+		},
+		{
+			name:          "no PCMU",
+			sdp:           "m=audio 0 RTP/AVP 97\na=rtpmap:97 MPEG4-GENERIC/16000\na=sendonly\n",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			control, pt, err := backchannelTrack(test.sdp)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if control != test.expectedControl || pt != test.expectedPT {
+				t.Errorf("Expected '%v' %v, Received '%v' %v", test.expectedControl, test.expectedPT, control, pt)
+			}
+		})
+	}
+}
+
+func TestWavSamples(t *testing.T) {
+	wav := func(format uint16, rate uint32, samples []byte) []byte {
+		var b bytes.Buffer
+		b.WriteString("RIFF")
+		binary.Write(&b, binary.LittleEndian, uint32(36+len(samples))) //nolint:errcheck
+		b.WriteString("WAVEfmt ")
+		binary.Write(&b, binary.LittleEndian, []uint32{16})         //nolint:errcheck
+		binary.Write(&b, binary.LittleEndian, []uint16{format, 1})  //nolint:errcheck
+		binary.Write(&b, binary.LittleEndian, []uint32{rate, rate}) //nolint:errcheck
+		binary.Write(&b, binary.LittleEndian, []uint16{1, 8})       //nolint:errcheck
+		b.WriteString("data")
+		binary.Write(&b, binary.LittleEndian, uint32(len(samples))) //nolint:errcheck
+		b.Write(samples)
+		return b.Bytes()
+	}
+
+	samples := []byte{0xff, 0x7f, 0x00, 0x80}
+
+	data, err := wavSamples(wav(wavFormatMuLaw, pcmuClockRate, samples))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(data, samples) {
+		t.Errorf("Expected %v, Received %v", samples, data)
+	}
+
+	_, err = wavSamples(wav(1, 44100, samples))
+	if err == nil {
+		t.Errorf("Expected an error for PCM audio but didn't get one")
+	}
+
+	// streamed WAV files may give the largest size for a data chunk of unknown length
+	streamed := wav(wavFormatMuLaw, pcmuClockRate, samples)
+	binary.LittleEndian.PutUint32(streamed[40:], 0xffffffff)
+	data, err = wavSamples(streamed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(data, samples) {
+		t.Errorf("Expected %v, Received %v", samples, data)
+	}
+}
+
+func TestMetadataTrack(t *testing.T) {
+	tests := []struct {
+		name            string
+		sdp             string
+		expectedControl string
+		expectedError   bool
+	}{
+		{
+			name:            "metadata after video",
+			sdp:             "m=video 0 RTP/AVP 96\na=control:video\nm=application 0 RTP/AVP 107\na=control:metadata\na=rtpmap:107 vnd.onvif.metadata/90000\n",
+			expectedControl: "metadata",
+		},
+		{
+			name:            "metadata before audio",
+			sdp:             "m=application 0 RTP/AVP 98\na=rtpmap:98 VND.ONVIF.METADATA/90000\na=control:trackID=3\nm=audio 0 RTP/AVP 0\na=control:audio\n",
+			expectedControl: "trackID=3",
+		},
+		{
+			name:          "other application",
+			sdp:           "m=application 0 RTP/AVP 99\na=rtpmap:99 vnd.other/90000\na=control:other\n",
+			expectedError: true,
+		},
+		{
+			name:          "no metadata",
+			sdp:           testSDP,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			control, err := metadataTrack(test.sdp)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if control != test.expectedControl {
+				t.Errorf("Expected '%v', Received '%v'", test.expectedControl, control)
+			}
+		})
+	}
+}
+
+func TestSessionControl(t *testing.T) {
+	tests := []struct {
+		name     string
+		sdp      string
+		expected string
+	}{
+		{"session control", testSDP, "aggregate"},
+		{"wildcard", "v=0\r\na=control:*\r\nm=audio 0 RTP/AVP 0\r\na=control:audio\r\n", "*"},
+		{"media control only", "v=0\r\nm=audio 0 RTP/AVP 0\r\na=control:audio\r\n", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if control := sessionControl(test.sdp); control != test.expected {
+				t.Errorf("Expected '%v', Received '%v'", test.expected, control)
+			}
+		})
+	}
+}
+
+func TestRtpPayload(t *testing.T) {
+	header := []byte{0x80, 0x6b, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1}
+
+	tests := []struct {
+		name            string
+		packet          []byte
+		expectedPayload string
+		expectedMarker  bool
+		expectedError   bool
+	}{
+		{
+			name:            "payload",
+			packet:          append(append([]byte{}, header...), "<tt:"...),
+			expectedPayload: "<tt:",
+		},
+		{
+			name:            "marker",
+			packet:          append([]byte{0x80, 0xeb}, append(append([]byte{}, header[2:]...), "/>"...)...),
+			expectedPayload: "/>",
+			expectedMarker:  true,
+		},
+		{
+			name:            "header extension",
+			packet:          append([]byte{0x90, 0x6b}, append(append([]byte{}, header[2:]...), 0xab, 0xac, 0, 1, 1, 2, 3, 4, 'x')...),
+			expectedPayload: "x",
+		},
+		{
+			name:            "padding",
+			packet:          append([]byte{0xa0, 0x6b}, append(append([]byte{}, header[2:]...), 'y', 0, 2)...),
+			expectedPayload: "y",
+		},
+		{
+			name:          "short",
+			packet:        header[:8],
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			payload, marker, err := rtpPayload(test.packet)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(payload) != test.expectedPayload || marker != test.expectedMarker {
+				t.Errorf("Expected '%v' %v, Received '%v' %v", test.expectedPayload, test.expectedMarker, string(payload), marker)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package rtsp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+)
+
+const wavFormatMuLaw = 7
+
+// LoadPCMU reads a G.711 µ-law audio clip, either as raw 8 kHz mono samples or as a WAV file
+// in that format, and returns its samples
+func LoadPCMU(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("rtsp: reading audio clip: %v", err.Error())
+	}
+
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return data, nil
+	}
+
+	return wavSamples(data)
+}
+
+// wavSamples returns the data chunk of a WAV file holding 8 kHz mono G.711 µ-law audio
+func wavSamples(data []byte) ([]byte, error) {
+	var formatChecked bool
+	for i := 12; i+8 <= len(data); {
+		id := string(data[i : i+4])
+		start := i + 8
+
+		// the chunk size is compared unconverted, as it may exceed int on 32 bit platforms
+		size := len(data) - start
+		if chunkSize := uint64(binary.LittleEndian.Uint32(data[i+4 : i+8])); chunkSize < uint64(size) {
+			size = int(chunkSize)
+		}
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, fmt.Errorf("rtsp: malformed WAV format chunk")
+			}
+			format := binary.LittleEndian.Uint16(data[start : start+2])
+			channels := binary.LittleEndian.Uint16(data[start+2 : start+4])
+			rate := binary.LittleEndian.Uint32(data[start+4 : start+8])
+			if format != wavFormatMuLaw || channels != 1 || rate != pcmuClockRate {
+				return nil, fmt.Errorf("rtsp: audio clip must be 8 kHz mono G.711 µ-law, got format %d, %d channels, %d Hz", format, channels, rate)
+			}
+			formatChecked = true
+		case "data":
+			if !formatChecked {
+				return nil, fmt.Errorf("rtsp: WAV data chunk precedes format chunk")
+			}
+			return data[start : start+size], nil
+		}
+
+		// chunks are padded to an even size
+		i = start + size + size%2
+	}
+
+	return nil, fmt.Errorf("rtsp: WAV file has no data chunk")
+}
@@ -0,0 +1,205 @@
+package rtsp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	metadataEncoding = "vnd.onvif.metadata"
+
+	// keepAliveInterval is well below the 60 second session timeout most cameras use
+	keepAliveInterval = 30 * time.Second
+
+	// maxMetadataSize bounds a metadata document reassembled from RTP packets
+	maxMetadataSize = 1 << 20
+)
+
+// ReadMetadata receives the ONVIF metadata stream of the URI and calls handle with each complete
+// XML document, typically a tt:MetadataStream holding the frames of one batch.  It returns when
+// the connection fails, or with nil once stop is closed.
+func (c *Client) ReadMetadata(handle func(document []byte), stop <-chan bool) error {
+	err := c.dial()
+	if err != nil {
+		return err
+	}
+	defer c.conn.Close()
+
+	resp, err := c.describe()
+	if err != nil {
+		return err
+	}
+
+	control, err := metadataTrack(string(resp.body))
+	if err != nil {
+		return err
+	}
+
+	resp, err = c.do("SETUP", c.controlURL(resp, control), map[string]string{"Transport": "RTP/AVP/TCP;unicast;interleaved=0-1"})
+	if err != nil {
+		return err
+	}
+
+	c.session = strings.Split(resp.header.Get("Session"), ";")[0]
+	channel := interleavedChannel(resp.header.Get("Transport"))
+
+	_, err = c.do("PLAY", c.aggregate, map[string]string{"Range": "npt=0.000-"})
+	if err != nil {
+		return err
+	}
+
+	// the session is kept alive, and closed when stopped, from a separate goroutine as reading
+	// blocks; keep-alive responses are skipped by readInterleaved
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(keepAliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if c.send("GET_PARAMETER", c.aggregate, nil) != nil {
+					return
+				}
+			case <-stop:
+				_ = c.send("TEARDOWN", c.aggregate, nil)
+				c.conn.Close()
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var document []byte
+	for {
+		ch, packet, err := c.readInterleaved()
+		if err != nil {
+			select {
+			case <-stop:
+				return nil
+			default:
+				return err
+			}
+		}
+		if ch != channel {
+			continue
+		}
+
+		payload, marker, err := rtpPayload(packet)
+		if err != nil {
+			continue
+		}
+
+		document = append(document, payload...)
+		if len(document) > maxMetadataSize {
+			return fmt.Errorf("rtsp: metadata document exceeds %d bytes", maxMetadataSize)
+		}
+
+		// the marker bit is set on the last packet of each XML document
+		if marker {
+			handle(document)
+			document = nil
+		}
+	}
+}
+
+// readInterleaved returns the next interleaved packet and its channel, skipping any RTSP
+// responses sent on the connection
+func (c *Client) readInterleaved() (byte, []byte, error) {
+	r := c.reader.R
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if b[0] != '$' {
+			_, err = c.readResponse()
+			if err != nil {
+				return 0, nil, err
+			}
+			continue
+		}
+
+		return readInterleavedPacket(r)
+	}
+}
+
+func readInterleavedPacket(r *bufio.Reader) (byte, []byte, error) {
+	var header [4]byte
+	_, err := io.ReadFull(r, header[:])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	packet := make([]byte, binary.BigEndian.Uint16(header[2:]))
+	_, err = io.ReadFull(r, packet)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return header[1], packet, nil
+}
+
+// rtpPayload returns the payload of an RTP packet and whether its marker bit is set
+func rtpPayload(packet []byte) ([]byte, bool, error) {
+	if len(packet) < 12 || packet[0]>>6 != 2 {
+		return nil, false, fmt.Errorf("rtsp: invalid RTP packet")
+	}
+
+	offset := 12 + 4*int(packet[0]&0x0f)
+	if packet[0]&0x10 != 0 && len(packet) >= offset+4 {
+		// header extension
+		offset += 4 + 4*int(binary.BigEndian.Uint16(packet[offset+2:]))
+	}
+
+	end := len(packet)
+	if packet[0]&0x20 != 0 && end > 0 {
+		// padding
+		end -= int(packet[end-1])
+	}
+	if offset > end {
+		return nil, false, fmt.Errorf("rtsp: invalid RTP packet")
+	}
+
+	return packet[offset:end], packet[1]&0x80 != 0, nil
+}
+
+// metadataTrack finds the ONVIF metadata track in an SDP description and returns its control
+// attribute
+func metadataTrack(sdp string) (string, error) {
+	var inApplication, metadata bool
+	var control string
+
+	for _, line := range strings.Split(sdp, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "m="):
+			if inApplication && metadata {
+				return control, nil
+			}
+			inApplication = strings.HasPrefix(line, "m=application")
+			metadata, control = false, ""
+		case !inApplication:
+			continue
+		case strings.HasPrefix(line, "a=control:"):
+			control = strings.TrimPrefix(line, "a=control:")
+		case strings.HasPrefix(line, "a=rtpmap:"):
+			fields := strings.Fields(strings.TrimPrefix(line, "a=rtpmap:"))
+			if len(fields) == 2 && strings.HasPrefix(strings.ToLower(fields[1]), metadataEncoding) {
+				metadata = true
+			}
+		}
+	}
+
+	if inApplication && metadata {
+		return control, nil
+	}
+	return "", fmt.Errorf("rtsp: camera offers no ONVIF metadata stream")
+}